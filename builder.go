@@ -1,9 +1,14 @@
 package mysql
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+
+	"github.com/jmoiron/sqlx"
 )
 
 type Builder struct {
@@ -15,6 +20,11 @@ type Builder struct {
 	// 查询字段
 	columns []string
 
+	// 查询的表达式，例如：MATCH (`title`) AGAINST (?) AS `score`
+	selects []string
+
+	selectBindings []interface{}
+
 	// 查询表
 	from string
 
@@ -25,18 +35,67 @@ type Builder struct {
 
 	joins []string
 
+	joinBindings []interface{}
+
 	// 分组
 	groups []string
 
+	// 分组统计 WITH ROLLUP
+	rollup bool
+
 	// hav 条件
 	havings []string
 
+	havingBindings []interface{}
+
+	// 命名窗口 WINDOW `w` AS (...)
+	windows []string
+
 	// 分组
 	orders []string
 
+	orderBindings []interface{}
+
 	limit string
 
 	offset string
+
+	// 最后一个全文搜索的 MATCH ... AGAINST 表达式
+	fullText *Expression
+
+	// 全文搜索相关度的别名
+	relevance string
+
+	// 软删除字段，为空时不使用软删除
+	deletedAt string
+
+	// 查询包含软删除的数据
+	withTrashed bool
+
+	// 只查询软删除的数据
+	onlyTrashed bool
+
+	// 预加载的关联
+	with []string
+
+	// 构建查询过程中的错误
+	errs []error
+}
+
+// whereOperators Where 支持的运算符
+var whereOperators = map[string]bool{
+	"=":          true,
+	"!=":         true,
+	"<>":         true,
+	">":          true,
+	">=":         true,
+	"<":          true,
+	"<=":         true,
+	"<=>":        true,
+	"LIKE":       true,
+	"NOT LIKE":   true,
+	"REGEXP":     true,
+	"NOT REGEXP": true,
 }
 
 func NewBuilder(db *MySQl, model interface{}) *Builder {
@@ -44,27 +103,67 @@ func NewBuilder(db *MySQl, model interface{}) *Builder {
 	m, err := GetModel(model)
 	if err == nil {
 		builder.from = m.TableName()
+		builder.deletedAt = GetDeletedAtColumnName(m)
 	}
 
 	return builder
 }
 
+// Clone 复制查询对象，复制后的查询条件互不影响，db 和 data 与原查询对象共用
+func (b *Builder) Clone() *Builder {
+	return &Builder{
+		db:             b.db,
+		data:           b.data,
+		columns:        cloneStrings(b.columns),
+		selects:        cloneStrings(b.selects),
+		selectBindings: cloneInterfaces(b.selectBindings),
+		from:           b.from,
+		wheres:         cloneStrings(b.wheres),
+		bindings:       cloneInterfaces(b.bindings),
+		joins:          cloneStrings(b.joins),
+		joinBindings:   cloneInterfaces(b.joinBindings),
+		groups:         cloneStrings(b.groups),
+		havings:        cloneStrings(b.havings),
+		rollup:         b.rollup,
+		havingBindings: cloneInterfaces(b.havingBindings),
+		windows:        cloneStrings(b.windows),
+		orders:         cloneStrings(b.orders),
+		orderBindings:  cloneInterfaces(b.orderBindings),
+		limit:          b.limit,
+		offset:         b.offset,
+		fullText:       b.fullText,
+		relevance:      b.relevance,
+		deletedAt:      b.deletedAt,
+		withTrashed:    b.withTrashed,
+		onlyTrashed:    b.onlyTrashed,
+		with:           cloneStrings(b.with),
+		errs:           append([]error(nil), b.errs...),
+	}
+}
+
 func (b *Builder) Select(column interface{}, columns ...string) *Builder {
 	if v, ok := column.([]string); ok {
-		b.columns = append(b.columns, v...)
+		columns = append(cloneStrings(v), columns...)
 	} else if v, ok := column.(string); ok {
-		b.columns = append(b.columns, v)
+		columns = append([]string{v}, columns...)
+	} else {
+		b.addError(fmt.Errorf("select column must be a string or []string, got %T", column))
 	}
 
-	if len(columns) > 0 {
-		b.columns = append(b.columns, columns...)
+	for _, v := range columns {
+		if b.identifier(v) {
+			b.columns = append(b.columns, v)
+		}
 	}
 
 	return b
 }
 
 func (b *Builder) Table(table string) *Builder {
-	b.from = table
+	if b.identifier(table) {
+		b.from = table
+	}
+
 	return b
 }
 
@@ -76,31 +175,95 @@ func (b *Builder) Where(column interface{}, args ...interface{}) *Builder {
 	return b.toWhere("AND", column, args...)
 }
 
-func (b *Builder) Join(table, on string, args ...interface{}) *Builder {
+// When 条件成立时才执行回调添加查询条件
+func (b *Builder) When(condition bool, fn func(builder *Builder)) *Builder {
+	if condition {
+		fn(b)
+	}
+
+	return b
+}
+
+// Scopes 依次执行封装好的查询条件，例如：Scopes(Active, Tenant(1))
+func (b *Builder) Scopes(fns ...func(builder *Builder) *Builder) *Builder {
+	for _, fn := range fns {
+		fn(b)
+	}
+
+	return b
+}
+
+// Join 关联查询，on 可以是字符串或者 func(*JoinClause)
+func (b *Builder) Join(table string, on interface{}, args ...interface{}) *Builder {
 	return b.toJoin("JOIN", table, on, args...)
 }
 
-func (b *Builder) LeftJoin(table, on string, args ...interface{}) *Builder {
+func (b *Builder) LeftJoin(table string, on interface{}, args ...interface{}) *Builder {
 	return b.toJoin("LEFT JOIN", table, on, args...)
 }
 
-func (b *Builder) RightJoin(table, on string, args ...interface{}) *Builder {
+func (b *Builder) RightJoin(table string, on interface{}, args ...interface{}) *Builder {
 	return b.toJoin("RIGHT JOIN", table, on, args...)
 }
 
+// CrossJoin 笛卡尔积关联
+func (b *Builder) CrossJoin(table string) *Builder {
+	return b.toJoin("CROSS JOIN", table, nil)
+}
+
+// JoinSub 关联子查询，alias 为子查询的别名
+func (b *Builder) JoinSub(query *Builder, alias string, on interface{}, args ...interface{}) *Builder {
+	return b.toJoinSub("JOIN", query, alias, on, args...)
+}
+
+func (b *Builder) LeftJoinSub(query *Builder, alias string, on interface{}, args ...interface{}) *Builder {
+	return b.toJoinSub("LEFT JOIN", query, alias, on, args...)
+}
+
+func (b *Builder) RightJoinSub(query *Builder, alias string, on interface{}, args ...interface{}) *Builder {
+	return b.toJoinSub("RIGHT JOIN", query, alias, on, args...)
+}
+
+// OrderBy 排序，direction 只能是 asc 或者 desc
 func (b *Builder) OrderBy(column, direction string) *Builder {
-	b.orders = append(b.orders, fmt.Sprintf("%s %s", b.warp(column), strings.ToUpper(direction)))
+	direction = strings.ToUpper(strings.TrimSpace(direction))
+	if direction != "ASC" && direction != "DESC" {
+		return b.addError(fmt.Errorf("order by %s has invalid direction %q", column, direction))
+	}
+
+	if b.identifier(column) {
+		b.orders = append(b.orders, fmt.Sprintf("%s %s", b.warp(column), direction))
+	}
+
+	return b
+}
+
+// OrderBySafe 使用用户输入排序，只允许 allowed 中的字段，不合法的排序会被忽略
+// 支持格式：username、-username、username:desc、username desc，多个排序使用逗号分隔
+func (b *Builder) OrderBySafe(input string, allowed []string) *Builder {
+	for _, item := range strings.Split(input, ",") {
+		column, direction := parseOrder(item)
+		if column != "" && InStringSlice(allowed, column) {
+			b.OrderBy(column, direction)
+		}
+	}
+
 	return b
 }
 
 func (b *Builder) GroupBy(groups ...string) *Builder {
-	b.groups = append(b.groups, groups...)
+	for _, v := range groups {
+		if b.identifier(v) {
+			b.groups = append(b.groups, v)
+		}
+	}
+
 	return b
 }
 
 func (b *Builder) Having(having string, args ...interface{}) *Builder {
 	b.havings = append(b.havings, having)
-	b.bindings = append(b.bindings, args...)
+	b.havingBindings = append(b.havingBindings, args...)
 	return b
 }
 
@@ -110,6 +273,8 @@ func (b *Builder) Limit(limit ...int) *Builder {
 		b.limit = fmt.Sprintf(" LIMIT %d", limit[0])
 	case 2:
 		b.limit = fmt.Sprintf(" LIMIT %d, %d", limit[0], limit[1])
+	default:
+		b.addError(fmt.Errorf("limit needs 1 or 2 arguments, got %d", len(limit)))
 	}
 
 	return b
@@ -121,93 +286,635 @@ func (b *Builder) Offset(offset int) *Builder {
 }
 
 func (b *Builder) One() error {
-	b.limit = " LIMIT 1"
-	return b.db.Get(b.data, fmt.Sprintf("%s", b), b.bindings...)
+	if err := b.check(); err != nil {
+		return err
+	}
+
+	if err := b.db.Get(b.data, b.Clone().Limit(1).String(), b.getBindings()...); err != nil {
+		return err
+	}
+
+	if err := b.eagerLoad(); err != nil {
+		return err
+	}
+
+	return found(b.db, b.data)
 }
 
 func (b *Builder) All() error {
-	return b.db.Select(b.data, b.String(), b.bindings...)
+	if err := b.check(); err != nil {
+		return err
+	}
+
+	if err := b.db.Select(b.data, b.String(), b.getBindings()...); err != nil {
+		return err
+	}
+
+	if err := b.eagerLoad(); err != nil {
+		return err
+	}
+
+	return found(b.db, b.data)
 }
 
-func (b *Builder) Paginate(page, size int) (int64, error) {
-	if page <= 0 {
-		page = 1
+// Chunk 按主键分批查询数据，每批数据写入 b.data 后执行回调，回调返回错误时停止查询
+// 分批查询按主键升序，会忽略设置的排序、分组和 limit
+func (b *Builder) Chunk(size int, fn func(batch interface{}) error) error {
+	if err := b.check(); err != nil {
+		return err
 	}
 
-	offset := (page - 1) * size
-	b.Offset(offset)
-	b.Limit(size)
+	model, err := GetModel(b.data)
+	if err != nil {
+		return err
+	}
 
-	// 查询总数
-	sql := fmt.Sprintf(
-		"SELECT COUNT(*) AS `total` FROM %s%s%s%s%s",
-		b.warp(b.from),
-		strings.Join(b.joins, ""),
-		b.whereFormat(true),
-		b.groupByFormat(),
-		b.havingFormat(),
+	if size <= 0 {
+		size = DefaultPageSize
+	}
+
+	var (
+		lastID interface{}
+		value  = reflect.ValueOf(b.data).Elem()
 	)
 
-	var total int64
-	if err := b.db.Get(&total, sql, b.bindings...); err != nil {
+	for {
+		value.Set(reflect.MakeSlice(value.Type(), 0, size))
+		query, bindings := b.chunkFormat(model, lastID, size)
+		if err := b.db.Select(b.data, query, bindings...); err != nil {
+			return err
+		}
+
+		length := value.Len()
+		if length == 0 {
+			return nil
+		}
+
+		if err := b.eagerLoad(); err != nil {
+			return err
+		}
+
+		if err := found(b.db, b.data); err != nil {
+			return err
+		}
+
+		if err := fn(b.data); err != nil {
+			return err
+		}
+
+		if length < size {
+			return nil
+		}
+
+		last := value.Index(length - 1)
+		if last.Kind() != reflect.Ptr {
+			last = last.Addr()
+		}
+
+		// 主键没有查询或者没有增大时会一直查询同一页
+		id := GetPKValue(last.Interface().(Model))
+		if id == nil || reflect.ValueOf(id).IsZero() || reflect.DeepEqual(id, lastID) {
+			return fmt.Errorf("chunk needs an increasing primary key %s, got %v", model.PK(), id)
+		}
+
+		lastID = id
+	}
+}
+
+// Cursor 查询数据并返回游标，逐行读取数据，使用完成后需要关闭游标
+func (b *Builder) Cursor() (*Cursor, error) {
+	if err := b.check(); err != nil {
+		return nil, err
+	}
+
+	rows, err := b.db.Queryx(b.String(), b.getBindings()...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cursor{db: b.db, rows: rows, typ: structType(b.data)}, nil
+}
+
+// Paginate 分页查询，返回总数
+func (b *Builder) Paginate(page, size int) (int64, error) {
+	pagination, err := b.Pagination(page, size)
+	if err != nil {
 		return 0, err
 	}
 
+	return pagination.Total, nil
+}
+
+// Pagination 分页查询，返回分页信息
+func (b *Builder) Pagination(page, size int) (*Pagination, error) {
+	if err := b.check(); err != nil {
+		return nil, err
+	}
+
+	pagination := NewPagination(page, size)
+
+	// 查询总数
+	var total int64
+	query, bindings := b.countSQL()
+	if err := b.db.Get(&total, query, bindings...); err != nil {
+		return nil, err
+	}
+
+	pagination.SetTotal(total)
+
 	// 查询数量大于0，才去查询具体数据
 	if total > 0 {
-		if err := b.All(); err != nil {
-			return 0, err
+		if err := b.Clone().Offset(pagination.Offset()).Limit(pagination.Size).All(); err != nil {
+			return nil, err
 		}
 	}
 
-	return total, nil
+	return pagination, nil
+}
+
+// SimplePagination 分页查询，不查询总数，多查询一条数据判断是否还有下一页
+func (b *Builder) SimplePagination(page, size int) (*Pagination, error) {
+	if err := b.check(); err != nil {
+		return nil, err
+	}
+
+	pagination := NewPagination(page, size)
+	if err := b.Clone().Offset(pagination.Offset()).Limit(pagination.Size + 1).All(); err != nil {
+		return nil, err
+	}
+
+	// 多查询出来的一条数据需要去掉
+	value := reflect.Indirect(reflect.ValueOf(b.data))
+	if value.Kind() == reflect.Slice && value.Len() > pagination.Size {
+		pagination.HasMore = true
+		value.Set(value.Slice(0, pagination.Size))
+	}
+
+	return pagination, nil
 }
 
 func (b *Builder) Update(zeroColumn ...string) (int64, error) {
-	setColumns, args := ToQueryWhere(b.data, nil, zeroColumn)
-	args = append(args, b.bindings...)
-	return b.db.Exec(fmt.Sprintf("UPDATE %s SET %s%s", b.warp(b.from), strings.Join(setColumns, ","), b.whereFormat(true)), args...)
+	query, args, err := b.updateSQL(zeroColumn)
+	if err != nil {
+		return 0, err
+	}
+
+	return b.db.Exec(query, args...)
 }
 
+// Delete 删除数据，模型实现了 SoftDeletes 时为软删除
 func (b *Builder) Delete() (int64, error) {
-	return b.db.Exec(fmt.Sprintf("DELETE FROM %s%s", b.warp(b.from), b.whereFormat(true)), b.bindings...)
+	query, args, err := b.deleteSQL()
+	if err != nil {
+		return 0, err
+	}
+
+	return b.db.Exec(query, args...)
+}
+
+// ForceDelete 删除数据，模型实现了 SoftDeletes 时也会物理删除
+func (b *Builder) ForceDelete() (int64, error) {
+	query, args, err := b.forceDeleteSQL()
+	if err != nil {
+		return 0, err
+	}
+
+	return b.db.Exec(query, args...)
+}
+
+// Restore 恢复软删除的数据
+func (b *Builder) Restore() (int64, error) {
+	if b.deletedAt == "" {
+		return 0, errors.New("restore needs a model implementing SoftDeletes")
+	}
+
+	builder := b.Clone().OnlyTrashed()
+	query, args, err := builder.updateMapSQL(map[string]interface{}{b.deletedAt: nil})
+	if err != nil {
+		return 0, err
+	}
+
+	return b.db.Exec(query, args...)
+}
+
+// WithTrashed 查询包含软删除的数据
+func (b *Builder) WithTrashed() *Builder {
+	b.withTrashed, b.onlyTrashed = true, false
+	return b
+}
+
+// OnlyTrashed 只查询软删除的数据
+func (b *Builder) OnlyTrashed() *Builder {
+	b.withTrashed, b.onlyTrashed = false, true
+	return b
+}
+
+// UpdateMap 使用 map 修改数据，值可以是 Raw 表达式，例如：map[string]interface{}{"balance": Raw("`balance` - ?", 10)}
+func (b *Builder) UpdateMap(values map[string]interface{}) (int64, error) {
+	query, args, err := b.updateMapSQL(values)
+	if err != nil {
+		return 0, err
+	}
+
+	return b.db.Exec(query, args...)
+}
+
+// Increment 字段自增 amount，extra 为同时修改的其他字段
+func (b *Builder) Increment(column string, amount interface{}, extra ...map[string]interface{}) (int64, error) {
+	return b.UpdateMap(b.incrementValues(column, "+", amount, extra))
+}
+
+// Decrement 字段自减 amount，extra 为同时修改的其他字段
+func (b *Builder) Decrement(column string, amount interface{}, extra ...map[string]interface{}) (int64, error) {
+	return b.UpdateMap(b.incrementValues(column, "-", amount, extra))
+}
+
+// ToSQL 获取查询的SQL和绑定参数，IN 查询的参数会和执行时一样展开
+func (b *Builder) ToSQL() (string, []interface{}, error) {
+	if err := b.check(); err != nil {
+		return "", nil, err
+	}
+
+	return sqlx.In(b.String(), b.getBindings()...)
+}
+
+// ToCountSQL 获取分页查询总数的SQL和绑定参数
+func (b *Builder) ToCountSQL() (string, []interface{}, error) {
+	if err := b.check(); err != nil {
+		return "", nil, err
+	}
+
+	query, bindings := b.countSQL()
+	return sqlx.In(query, bindings...)
+}
+
+// ToUpdateSQL 获取 Update 执行的SQL和绑定参数
+func (b *Builder) ToUpdateSQL(zeroColumn ...string) (string, []interface{}, error) {
+	query, args, err := b.updateSQL(zeroColumn)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return sqlx.In(query, args...)
+}
+
+// ToUpdateMapSQL 获取 UpdateMap 执行的SQL和绑定参数
+func (b *Builder) ToUpdateMapSQL(values map[string]interface{}) (string, []interface{}, error) {
+	query, args, err := b.updateMapSQL(values)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return sqlx.In(query, args...)
+}
+
+// ToDeleteSQL 获取 Delete 执行的SQL和绑定参数
+func (b *Builder) ToDeleteSQL() (string, []interface{}, error) {
+	query, args, err := b.deleteSQL()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return sqlx.In(query, args...)
+}
+
+// ToInsertSQL 获取新增 data 数据的SQL和绑定参数，零值字段不会写入，除非在 zeroColumn 中指定
+func (b *Builder) ToInsertSQL(zeroColumn ...string) (string, []interface{}, error) {
+	query, args, err := b.insertSQL(zeroColumn)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return sqlx.In(query, args...)
+}
+
+// Err 构建查询过程中记录的错误，没有错误时返回 nil
+func (b *Builder) Err() error {
+	if len(b.errs) == 0 {
+		return nil
+	}
+
+	return BuilderError(b.errs)
 }
 
 func (b *Builder) String() string {
 	return fmt.Sprintf(
-		"SELECT %s FROM %s%s%s%s%s%s%s%s",
+		"SELECT %s FROM %s%s%s%s%s%s%s%s%s",
 		b.columnsFormat(),
 		b.warp(b.from),
 		strings.Join(b.joins, ""),
 		b.whereFormat(true),
 		b.groupByFormat(),
 		b.havingFormat(),
+		b.windowFormat(),
 		b.orderByFormat(),
 		b.limit,
 		b.offset,
 	)
 }
 
+// chunkFormat 分批查询的SQL，从上一批最后一条数据的主键之后开始查询
+func (b *Builder) chunkFormat(model Model, lastID interface{}, size int) (string, []interface{}) {
+	pk := model.PK()
+	if len(b.joins) > 0 {
+		pk = tableAlias(b.from) + "." + pk
+	}
+
+	where := b.whereFormat(false)
+	bindings := mergeBindings(b.selectBindings, b.joinBindings, b.bindings)
+	if lastID != nil {
+		if where != "" {
+			where = fmt.Sprintf("(%s) AND ", where)
+		}
+
+		where += fmt.Sprintf("%s > ?", b.warp(pk))
+		bindings = append(bindings, lastID)
+	}
+
+	if where != "" {
+		where = " WHERE " + where
+	}
+
+	// 指定了查询字段时需要查询主键，用于查询下一页
+	columns := b.columnsFormat()
+	if len(b.columns) > 0 && !selectsColumn(b.columns, model.PK()) {
+		columns += ", " + b.warp(pk)
+	}
+
+	return fmt.Sprintf(
+		"SELECT %s FROM %s%s%s%s ORDER BY %s ASC LIMIT %d",
+		columns,
+		b.warp(b.from),
+		strings.Join(b.joins, ""),
+		where,
+		b.windowFormat(),
+		b.warp(pk),
+		size,
+	), bindings
+}
+
+// selectsColumn 查询字段是否包含指定字段，包括 * 和 t.*，字段设置了别名时使用别名判断
+func selectsColumn(columns []string, column string) bool {
+	for _, v := range columns {
+		name := tableAlias(v)
+		if index := strings.LastIndex(name, "."); index >= 0 {
+			name = name[index+1:]
+		}
+
+		if name = strings.Trim(name, "`"); name == "*" || name == column {
+			return true
+		}
+	}
+
+	return false
+}
+
+// countSQL 查询总数的SQL，存在分组时使用子查询统计分组后的数量
+func (b *Builder) countSQL() (string, []interface{}) {
+	if len(b.groups) == 0 && len(b.havings) == 0 {
+		return fmt.Sprintf(
+			"SELECT COUNT(*) AS `total` FROM %s%s%s",
+			b.warp(b.from),
+			strings.Join(b.joins, ""),
+			b.whereFormat(true),
+		), mergeBindings(b.joinBindings, b.bindings)
+	}
+
+	return fmt.Sprintf(
+		"SELECT COUNT(*) AS `total` FROM (SELECT %s FROM %s%s%s%s%s%s) AS `aggregate`",
+		b.columnsFormat(),
+		b.warp(b.from),
+		strings.Join(b.joins, ""),
+		b.whereFormat(true),
+		b.groupByFormat(),
+		b.havingFormat(),
+		b.windowFormat(),
+	), mergeBindings(b.selectBindings, b.joinBindings, b.bindings, b.havingBindings)
+}
+
+// updateSQL 使用 data 中非零值字段修改数据的SQL
+func (b *Builder) updateSQL(zeroColumn []string) (string, []interface{}, error) {
+	if err := b.checkData(); err != nil {
+		return "", nil, err
+	}
+
+	setColumns, args := ToUpdateColumns(b.data, nil, zeroColumn)
+
+	// 关联修改时，修改的字段需要指定表名
+	if len(b.joins) > 0 {
+		target := b.warp(tableAlias(b.from))
+		for k, v := range setColumns {
+			setColumns[k] = target + "." + v
+		}
+	}
+
+	return b.setSQL(setColumns, args)
+}
+
+// updateMapSQL 使用 map 修改数据的SQL
+func (b *Builder) updateMapSQL(values map[string]interface{}) (string, []interface{}, error) {
+	if err := b.check(); err != nil {
+		return "", nil, err
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if !IsIdentifier(key) {
+			return "", nil, fmt.Errorf("invalid identifier %q", key)
+		}
+
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	setColumns := make([]string, 0, len(keys))
+	args := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		if expression, ok := values[key].(Expression); ok {
+			if expression.err != nil {
+				return "", nil, expression.err
+			}
+
+			setColumns = append(setColumns, fmt.Sprintf("%s = %s", b.setColumn(key), expression.SQL))
+			args = append(args, expression.Args...)
+			continue
+		}
+
+		setColumns = append(setColumns, fmt.Sprintf("%s = ?", b.setColumn(key)))
+		args = append(args, values[key])
+	}
+
+	return b.setSQL(setColumns, args)
+}
+
+// setSQL 修改数据的SQL，setColumns 为 `name` = ? 格式的修改字段，关联修改时字段已经指定了表名
+func (b *Builder) setSQL(setColumns []string, args []interface{}) (string, []interface{}, error) {
+	if len(setColumns) == 0 {
+		return "", nil, errors.New("update needs at least one column to set")
+	}
+
+	if err := b.checkLimit("update"); err != nil {
+		return "", nil, err
+	}
+
+	bindings := mergeBindings(b.joinBindings, args, b.bindings, b.orderBindings)
+	return fmt.Sprintf(
+		"UPDATE %s%s SET %s%s%s%s",
+		b.warp(b.from),
+		strings.Join(b.joins, ""),
+		strings.Join(setColumns, ","),
+		b.whereFormat(true),
+		b.orderByFormat(),
+		b.limit,
+	), bindings, nil
+}
+
+// incrementValues 自增、自减需要修改的字段
+func (b *Builder) incrementValues(column, operator string, amount interface{}, extra []map[string]interface{}) map[string]interface{} {
+	values := make(map[string]interface{})
+	for _, item := range extra {
+		for k, v := range item {
+			values[k] = v
+		}
+	}
+
+	values[column] = Raw(fmt.Sprintf("%s %s ?", b.setColumn(column), operator), amount)
+	return values
+}
+
+// setColumn 修改的字段，关联修改时没有指定表名的字段使用主表的表名
+func (b *Builder) setColumn(column string) string {
+	if len(b.joins) > 0 && strings.Index(column, ".") == -1 {
+		return b.warp(tableAlias(b.from)) + "." + b.warp(column)
+	}
+
+	return b.warp(column)
+}
+
+// deleteSQL 删除数据的SQL，软删除时修改删除时间
+func (b *Builder) deleteSQL() (string, []interface{}, error) {
+	if b.deletedAt == "" {
+		return b.forceDeleteSQL()
+	}
+
+	if err := b.check(); err != nil {
+		return "", nil, err
+	}
+
+	model, err := GetModel(b.data)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return b.setSQL([]string{fmt.Sprintf("%s = ?", b.setColumn(b.deletedAt))}, []interface{}{GetTimestampsValue(model)})
+}
+
+// forceDeleteSQL 物理删除数据的SQL，关联删除时只删除主表的数据
+func (b *Builder) forceDeleteSQL() (string, []interface{}, error) {
+	if err := b.check(); err != nil {
+		return "", nil, err
+	}
+
+	if err := b.checkLimit("delete"); err != nil {
+		return "", nil, err
+	}
+
+	if len(b.joins) > 0 {
+		return fmt.Sprintf(
+			"DELETE %s FROM %s%s%s",
+			b.warp(tableAlias(b.from)),
+			b.warp(b.from),
+			strings.Join(b.joins, ""),
+			b.whereFormat(true),
+		), mergeBindings(b.joinBindings, b.bindings), nil
+	}
+
+	return fmt.Sprintf(
+		"DELETE FROM %s%s%s%s",
+		b.warp(b.from),
+		b.whereFormat(true),
+		b.orderByFormat(),
+		b.limit,
+	), mergeBindings(b.bindings, b.orderBindings), nil
+}
+
+// checkLimit 检查修改、删除的排序和数量限制，MySQL 不支持 OFFSET，关联修改、删除不支持 ORDER BY 和 LIMIT
+func (b *Builder) checkLimit(operation string) error {
+	if b.offset != "" || strings.Index(b.limit, ",") != -1 {
+		return fmt.Errorf("%s does not support offset", operation)
+	}
+
+	if len(b.joins) > 0 && (len(b.orders) > 0 || b.limit != "") {
+		return fmt.Errorf("%s with join does not support order by and limit", operation)
+	}
+
+	return nil
+}
+
+// insertSQL 新增 data 数据的SQL
+func (b *Builder) insertSQL(zeroColumn []string) (string, []interface{}, error) {
+	if err := b.checkData(); err != nil {
+		return "", nil, err
+	}
+
+	fields := make([]string, 0)
+	bind := make([]string, 0)
+	args := make([]interface{}, 0)
+	for _, column := range StructColumns(b.data, "db") {
+		if column.insertable(zeroColumn) {
+			fields = append(fields, b.warp(column.Name))
+			bind = append(bind, "?")
+			args = append(args, column.Value)
+		}
+	}
+
+	if len(fields) == 0 {
+		return "", nil, errors.New("insert needs at least one column")
+	}
+
+	return fmt.Sprintf(
+		"INSERT INTO %s (%s) VALUES (%s)",
+		b.warp(b.from),
+		strings.Join(fields, ", "),
+		strings.Join(bind, ", "),
+	), args, nil
+}
+
+// getBindings 查询SQL的绑定参数，按照 SELECT、JOIN、WHERE、HAVING、ORDER BY 的顺序
+func (b *Builder) getBindings() []interface{} {
+	return mergeBindings(b.selectBindings, b.joinBindings, b.bindings, b.havingBindings, b.orderBindings)
+}
+
 func (b *Builder) columnsFormat() string {
-	if len(b.columns) == 0 {
-		return "*"
+	columns := make([]string, 0, len(b.columns)+len(b.selects)+1)
+	for _, v := range b.columns {
+		columns = append(columns, b.warp(v))
 	}
 
-	for k, v := range b.columns {
-		b.columns[k] = b.warp(v)
+	if len(columns) == 0 {
+		columns = append(columns, "*")
 	}
 
-	return strings.Join(b.columns, ", ")
+	return strings.Join(append(columns, b.selects...), ", ")
 }
 
 func (b *Builder) whereFormat(where bool) string {
-	if len(b.wheres) == 0 {
-		return ""
+	str := strings.Join(b.wheres, " ")
+	str = strings.TrimPrefix(str, "AND ")
+	str = strings.TrimPrefix(str, "OR ")
+
+	// 软删除条件
+	if scope := b.softDeleteFormat(); scope != "" {
+		if str != "" {
+			str = fmt.Sprintf("(%s) AND %s", str, scope)
+		} else {
+			str = scope
+		}
 	}
 
-	str := strings.Join(b.wheres, " ")
-	str = strings.TrimLeft(str, "AND ")
-	str = strings.TrimLeft(str, "OR ")
+	if str == "" {
+		return ""
+	}
 
 	if where {
 		return fmt.Sprintf(" WHERE %s", str)
@@ -215,16 +922,40 @@ func (b *Builder) whereFormat(where bool) string {
 
 	return str
 }
+
+// softDeleteFormat 软删除的查询条件
+func (b *Builder) softDeleteFormat() string {
+	if b.deletedAt == "" || b.withTrashed {
+		return ""
+	}
+
+	column := b.deletedAt
+	if len(b.joins) > 0 {
+		column = tableAlias(b.from) + "." + column
+	}
+
+	if b.onlyTrashed {
+		return fmt.Sprintf("%s IS NOT NULL", b.warp(column))
+	}
+
+	return fmt.Sprintf("%s IS NULL", b.warp(column))
+}
+
 func (b *Builder) groupByFormat() string {
 	if b.groups == nil {
 		return ""
 	}
 
+	groups := make([]string, len(b.groups))
 	for k, v := range b.groups {
-		b.groups[k] = b.warp(v)
+		groups[k] = b.warp(v)
+	}
+
+	if b.rollup {
+		return fmt.Sprintf(" GROUP BY %s WITH ROLLUP", strings.Join(groups, ", "))
 	}
 
-	return fmt.Sprintf(" GROUP BY %s", strings.Join(b.groups, ", "))
+	return fmt.Sprintf(" GROUP BY %s", strings.Join(groups, ", "))
 }
 
 func (b *Builder) havingFormat() string {
@@ -243,9 +974,47 @@ func (b *Builder) orderByFormat() string {
 	return fmt.Sprintf(" ORDER BY %s", strings.Join(b.orders, ", "))
 }
 
-func (b *Builder) toJoin(join, table, on string, args ...interface{}) *Builder {
-	b.joins = append(b.joins, fmt.Sprintf(" %s %s ON (%s)", join, b.warp(table), on))
-	b.bindings = append(b.bindings, args...)
+func (b *Builder) toJoin(join, table string, on interface{}, args ...interface{}) *Builder {
+	if !b.identifier(table) {
+		return b
+	}
+
+	return b.joinOn(join, b.warp(table), on, args...)
+}
+
+func (b *Builder) toJoinSub(join string, query *Builder, alias string, on interface{}, args ...interface{}) *Builder {
+	if !b.identifier(alias) {
+		return b
+	}
+
+	b.joinBindings = append(b.joinBindings, query.getBindings()...)
+	b.errs = append(b.errs, query.errs...)
+	return b.joinOn(join, fmt.Sprintf("(%s) AS %s", query.String(), b.warp(alias)), on, args...)
+}
+
+// joinOn 添加关联的 ON 条件
+func (b *Builder) joinOn(join, table string, on interface{}, args ...interface{}) *Builder {
+	switch v := on.(type) {
+	case nil:
+		b.joins = append(b.joins, fmt.Sprintf(" %s %s", join, table))
+	case string:
+		b.joins = append(b.joins, fmt.Sprintf(" %s %s ON (%s)", join, table, v))
+		b.joinBindings = append(b.joinBindings, args...)
+	case func(join *JoinClause):
+		clause := &JoinClause{builder: &Builder{}}
+		v(clause)
+		b.errs = append(b.errs, clause.builder.errs...)
+		if len(clause.builder.wheres) == 0 {
+			b.joins = append(b.joins, fmt.Sprintf(" %s %s", join, table))
+			return b
+		}
+
+		b.joins = append(b.joins, fmt.Sprintf(" %s %s ON (%s)", join, table, clause.builder.whereFormat(false)))
+		b.joinBindings = append(b.joinBindings, clause.builder.bindings...)
+	default:
+		b.addError(fmt.Errorf("join on must be a string or func(*JoinClause), got %T", on))
+	}
+
 	return b
 }
 
@@ -256,13 +1025,14 @@ func (b *Builder) toWhere(boolean string, column interface{}, args ...interface{
 		builder := fn(&Builder{})
 		b.wheres = append(b.wheres, fmt.Sprintf("%s (%s)", boolean, builder.whereFormat(false)))
 		b.bindings = append(b.bindings, builder.bindings...)
+		b.errs = append(b.errs, builder.errs...)
 		return b
 	}
 
 	// 字符串处理
 	field, ok := column.(string)
 	if !ok {
-		return b
+		return b.addError(fmt.Errorf("where column must be a string or func(*Builder) *Builder, got %T", column))
 	}
 
 	// 自己写的 status = ? and age = ?
@@ -273,6 +1043,10 @@ func (b *Builder) toWhere(boolean string, column interface{}, args ...interface{
 	}
 
 	l := len(args)
+	if l > 0 && !b.identifier(field) {
+		return b
+	}
+
 	switch l {
 	case 1: // Where("status", 1)
 		b.wheres = append(b.wheres, fmt.Sprintf("%s %s = ?", boolean, b.warp(field)))
@@ -280,21 +1054,44 @@ func (b *Builder) toWhere(boolean string, column interface{}, args ...interface{
 	case 0: // Where("status = 1")
 		b.wheres = append(b.wheres, fmt.Sprintf("%s (%s)", boolean, field))
 	default: // Where("status", "in", [1, 2, 3]) or Where("status", "between", 1, 2) or Where("age", ">", 1)
-		switch args[0].(string) {
-		case "in", "IN", "not in", "NOT IN":
-			b.wheres = append(b.wheres, fmt.Sprintf("%s %s %s (?)", boolean, b.warp(field), strings.ToUpper(args[0].(string))))
+		operator, ok := args[0].(string)
+		if !ok {
+			return b.addError(fmt.Errorf("where %s operator must be a string, got %T", field, args[0]))
+		}
+
+		operator = strings.ToUpper(strings.TrimSpace(operator))
+		switch operator {
+		case "IN", "NOT IN":
+			b.wheres = append(b.wheres, fmt.Sprintf("%s %s %s (?)", boolean, b.warp(field), operator))
 			if l > 2 {
 				b.bindings = append(b.bindings, args[1:])
 			} else {
 				b.bindings = append(b.bindings, args[1])
 			}
-		case "between", "BETWEEN", "NOT BETWEEN", "not between":
-			if l > 2 {
-				b.wheres = append(b.wheres, fmt.Sprintf("%s %s %s ? AND ?", boolean, b.warp(field), strings.ToUpper(args[0].(string))))
-				b.bindings = append(b.bindings, args[1:]...)
+		case "BETWEEN", "NOT BETWEEN":
+			values := args[1:]
+
+			// Where("status", "between", []int{1, 2})
+			if l == 2 {
+				values = toInterfaceSlice(args[1])
+			}
+
+			if len(values) != 2 {
+				return b.addError(fmt.Errorf("where %s %s needs 2 values, got %d", field, operator, len(values)))
 			}
+
+			b.wheres = append(b.wheres, fmt.Sprintf("%s %s %s ? AND ?", boolean, b.warp(field), operator))
+			b.bindings = append(b.bindings, values...)
 		default:
-			b.wheres = append(b.wheres, fmt.Sprintf("%s %s %s ?", boolean, b.warp(field), strings.ToUpper(args[0].(string))))
+			if !whereOperators[operator] {
+				return b.addError(fmt.Errorf("where %s has unsupported operator %q", field, args[0]))
+			}
+
+			if l != 2 {
+				return b.addError(fmt.Errorf("where %s %s needs 1 value, got %d", field, operator, l-1))
+			}
+
+			b.wheres = append(b.wheres, fmt.Sprintf("%s %s %s ?", boolean, b.warp(field), operator))
 			b.bindings = append(b.bindings, args[1])
 		}
 	}
@@ -302,36 +1099,133 @@ func (b *Builder) toWhere(boolean string, column interface{}, args ...interface{
 	return b
 }
 
+// addError 记录构建查询过程中的错误，在执行查询时返回
+func (b *Builder) addError(err error) *Builder {
+	b.errs = append(b.errs, err)
+	return b
+}
+
+// identifier 验证字段名称或者表名称，不合法时记录错误
+func (b *Builder) identifier(s string) bool {
+	if !IsIdentifier(s) {
+		b.addError(fmt.Errorf("invalid identifier %q", s))
+		return false
+	}
+
+	return true
+}
+
+// check 执行查询前检查是否存在错误
+func (b *Builder) check() error {
+	if err := b.Err(); err != nil {
+		return err
+	}
+
+	if b.from == "" {
+		return ErrMissingTable
+	}
+
+	return nil
+}
+
+// checkData 执行新增、修改前检查 data 是否为结构体指针
+func (b *Builder) checkData() error {
+	if err := b.check(); err != nil {
+		return err
+	}
+
+	if value := reflect.ValueOf(b.data); value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("data must be a non-nil pointer to struct, got %T", b.data)
+	}
+
+	return nil
+}
+
 func (b *Builder) warp(s string) string {
+	// JSON 字段 meta->>$.plan
+	if column, arrow, path, alias, ok := splitJSONColumn(s); ok {
+		s = fmt.Sprintf("%s%s'%s'", b.warp(column), arrow, path)
+		if alias != "" {
+			s += " AS " + b.warp(alias)
+		}
+
+		return s
+	}
+
 	// 自己带 `t`.`username`
 	if strings.Index(s, "`") != -1 {
 		return s
 	}
 
-	// table as username
-	if strings.Index(s, " as ") != -1 {
-		str := strings.Split(s, "as")
-		for k, v := range str {
-			str[k] = fmt.Sprintf("`%s`", strings.TrimSpace(v))
+	// table as t1、table AS t1、table t1 按照正则匹配的字段名称和别名添加引号
+	if matches := identifierRegexp.FindStringSubmatch(strings.TrimSpace(s)); matches != nil {
+		column := strings.Replace(fmt.Sprintf("`%s`", strings.Replace(matches[1], ".", "`.`", -1)), "`*`", "*", -1)
+		if alias := matches[8]; alias != "" {
+			if matches[7] != "" {
+				return column + " AS `" + alias + "`"
+			}
+
+			return column + " `" + alias + "`"
 		}
 
-		return strings.Join(str, " AS ")
+		return column
 	}
 
-	// table AS username
-	if strings.Index(s, " AS ") != -1 {
-		str := strings.Split(s, "AS")
-		for k, v := range str {
-			str[k] = fmt.Sprintf("`%s`", strings.TrimSpace(v))
-		}
+	return strings.Replace(fmt.Sprintf("`%s`", strings.Replace(s, ".", "`.`", -1)), "`*`", "*", -1)
+}
+
+func cloneStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+
+	return append(make([]string, 0, len(s)), s...)
+}
+
+func cloneInterfaces(s []interface{}) []interface{} {
+	if s == nil {
+		return nil
+	}
+
+	return append(make([]interface{}, 0, len(s)), s...)
+}
+
+// toInterfaceSlice 将 slice 转为 []interface{}，不是 slice 时返回只包含该值的 slice
+func toInterfaceSlice(value interface{}) []interface{} {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return []interface{}{value}
+	}
+
+	values := make([]interface{}, v.Len())
+	for i := range values {
+		values[i] = v.Index(i).Interface()
+	}
+
+	return values
+}
+
+// tableAlias 获取表的别名，没有别名时返回表名，例如：user as u 返回 u
+func tableAlias(table string) string {
+	fields := strings.Fields(table)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	return strings.Trim(fields[len(fields)-1], "`")
+}
 
-		return strings.Join(str, " AS ")
+// mergeBindings 合并绑定参数，返回新的 slice
+func mergeBindings(bindings ...[]interface{}) []interface{} {
+	length := 0
+	for _, v := range bindings {
+		length += len(v)
 	}
 
-	// t1 user
-	if strings.Index(s, " ") != -1 {
-		s = strings.Replace(s, " ", "` `", -1)
+	merged := make([]interface{}, 0, length)
+	for _, v := range bindings {
+		merged = append(merged, v...)
 	}
 
-	return fmt.Sprintf("`%s`", strings.Replace(s, ".", "`.`", -1))
+	return merged
 }
@@ -84,6 +84,12 @@ func TestBuilder_Select(t *testing.T) {
 
 	builder.Select("status", "created_at")
 	assert.Equal(t, 4, len(builder.columns))
+
+	// 不修改传入的 slice
+	base := []string{"a", "b", "c"}
+	builder = NewBuilder(my, &User{}).Select(base[:1], "username")
+	assert.Equal(t, []string{"a", "username"}, builder.columns)
+	assert.Equal(t, []string{"a", "b", "c"}, base)
 }
 
 func TestNewBuilder(t *testing.T) {
@@ -111,13 +117,12 @@ func TestBuilder_Where(t *testing.T) {
 		Where(func(builder *Builder) *Builder {
 			return builder.Where("status", 1).Where("status", "!=", 10)
 		}).
-		Where(1).
 		Where("status", "between", []int{1, 2}).
 		Where("updated_at", "between", "2020-11-22 16:19:11", DateTime()).
 		Where("created_at", ">=", "2020-11-14 22:18:37").
 		Where("password", "v123456")
 	fmt.Printf("%s\n", builder)
-	assert.Equal(t, 6, len(builder.wheres))
+	assert.Equal(t, 7, len(builder.wheres))
 
 	err := builder.One()
 	assert.NoError(t, err)
@@ -202,6 +207,26 @@ func TestBuilder_warp(t *testing.T) {
 			name: "t1 username",
 			want: "`t1` `username`",
 		},
+		{
+			name: "password as pw",
+			want: "`password` AS `pw`",
+		},
+		{
+			name: "classes as c",
+			want: "`classes` AS `c`",
+		},
+		{
+			name: "u.password AS pass",
+			want: "`u`.`password` AS `pass`",
+		},
+		{
+			name: "classes",
+			want: "`classes`",
+		},
+		{
+			name: "u.*",
+			want: "`u`.*",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -231,3 +256,531 @@ func TestBuilder_Where2(t *testing.T) {
 	fmt.Printf("%s \n", s)
 	assert.Equal(t, "SELECT * FROM `user` WHERE `user`.`username` = ? OR `user`.`password` = ?", s)
 }
+
+func TestBuilder_Pagination(t *testing.T) {
+	mySQL := NewTestMySQL(t, examplePathName, userPathName)
+	user := make([]*User, 0)
+
+	p, err := NewBuilder(mySQL, &user).Where("status", 1).Pagination(1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, &Pagination{Total: 3, Page: 1, Size: 2, LastPage: 2, HasMore: true}, p)
+	assert.Equal(t, 2, len(user))
+
+	// 分组统计的是分组后的数量
+	user = make([]*User, 0)
+	p, err = NewBuilder(mySQL, &user).Select("status").GroupBy("status").Pagination(1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), p.Total)
+	assert.Equal(t, 1, len(user))
+}
+
+func TestBuilder_SimplePagination(t *testing.T) {
+	mySQL := NewTestMySQL(t, examplePathName, userPathName)
+	user := make([]*User, 0)
+
+	p, err := NewBuilder(mySQL, &user).OrderBy("user_id", "asc").SimplePagination(1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, true, p.HasMore)
+	assert.Equal(t, int64(0), p.Total)
+	assert.Equal(t, 2, len(user))
+
+	user = make([]*User, 0)
+	p, err = NewBuilder(mySQL, &user).OrderBy("user_id", "asc").SimplePagination(2, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, false, p.HasMore)
+	assert.Equal(t, 1, len(user))
+}
+
+func TestBuilder_countSQL(t *testing.T) {
+	s, bindings := NewBuilder(&MySQl{}, &User{}).Where("status", 1).countSQL()
+	assert.Equal(t, "SELECT COUNT(*) AS `total` FROM `user` WHERE `status` = ?", s)
+	assert.Equal(t, []interface{}{1}, bindings)
+
+	s, bindings = NewBuilder(&MySQl{}, &User{}).Select("status").Where("status", 1).GroupBy("status").Having("COUNT(*) > ?", 1).countSQL()
+	assert.Equal(t, "SELECT COUNT(*) AS `total` FROM (SELECT `status` FROM `user` WHERE `status` = ? GROUP BY `status` HAVING COUNT(*) > ?) AS `aggregate`", s)
+	assert.Equal(t, []interface{}{1, 1}, bindings)
+}
+
+func TestBuilder_Chunk(t *testing.T) {
+	mySQL := NewTestMySQL(t, examplePathName, userPathName)
+	user := make([]*User, 0)
+
+	ids := make([]int64, 0)
+	batches := 0
+	err := NewBuilder(mySQL, &user).Where("status", 1).Chunk(2, func(batch interface{}) error {
+		batches++
+		for _, v := range *batch.(*[]*User) {
+			ids = append(ids, v.UserId)
+		}
+
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, batches)
+	assert.Equal(t, []int64{1, 2, 3}, ids)
+
+	// 回调返回错误时停止
+	batches = 0
+	err = NewBuilder(mySQL, &user).Chunk(1, func(batch interface{}) error {
+		batches++
+		return fmt.Errorf("stop")
+	})
+	assert.EqualError(t, err, "stop")
+	assert.Equal(t, 1, batches)
+
+	// 查询字段没有主键时也能查询下一页
+	names := make([]string, 0)
+	err = NewBuilder(mySQL, &user).Select("username").Where("status", 1).Chunk(2, func(batch interface{}) error {
+		for _, v := range *batch.(*[]*User) {
+			names = append(names, v.Username)
+		}
+
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(names))
+}
+
+func TestBuilder_chunkFormat(t *testing.T) {
+	b := NewBuilder(&MySQl{}, &User{}).Where("status", 1).OrWhere("status", 2)
+	query, bindings := b.chunkFormat(&User{}, nil, 100)
+	assert.Equal(t, "SELECT * FROM `user` WHERE `status` = ? OR `status` = ? ORDER BY `user_id` ASC LIMIT 100", query)
+	assert.Equal(t, []interface{}{1, 2}, bindings)
+
+	query, bindings = b.chunkFormat(&User{}, int64(10), 100)
+	assert.Equal(t, "SELECT * FROM `user` WHERE (`status` = ? OR `status` = ?) AND `user_id` > ? ORDER BY `user_id` ASC LIMIT 100", query)
+	assert.Equal(t, []interface{}{1, 2, int64(10)}, bindings)
+
+	// 表设置别名时主键使用别名
+	b = NewBuilder(&MySQl{}, &User{}).Table("user as u").Join("user as j", "j.user_id = u.user_id")
+	query, _ = b.chunkFormat(&User{}, int64(10), 100)
+	assert.Equal(t, "SELECT * FROM `user` AS `u` JOIN `user` AS `j` ON (j.user_id = u.user_id) WHERE `u`.`user_id` > ? ORDER BY `u`.`user_id` ASC LIMIT 100", query)
+
+	// 查询字段没有主键时添加主键
+	b = NewBuilder(&MySQl{}, &User{}).Select("username")
+	query, _ = b.chunkFormat(&User{}, int64(10), 2)
+	assert.Equal(t, "SELECT `username`, `user_id` FROM `user` WHERE `user_id` > ? ORDER BY `user_id` ASC LIMIT 2", query)
+
+	b = NewBuilder(&MySQl{}, &User{}).Table("user as u").Join("user as j", "j.user_id = u.user_id").Select("j.username")
+	query, _ = b.chunkFormat(&User{}, nil, 2)
+	assert.Equal(t, "SELECT `j`.`username`, `u`.`user_id` FROM `user` AS `u` JOIN `user` AS `j` ON (j.user_id = u.user_id) ORDER BY `u`.`user_id` ASC LIMIT 2", query)
+
+	for _, columns := range [][]string{{"user_id", "username"}, {"u.user_id"}, {"`u`.`user_id`"}, {"u.*"}, {"id as user_id"}} {
+		assert.True(t, selectsColumn(columns, "user_id"), "%v", columns)
+	}
+
+	assert.False(t, selectsColumn([]string{"user_id as id", "username"}, "user_id"))
+}
+
+func TestBuilder_Cursor(t *testing.T) {
+	mySQL := NewTestMySQL(t, examplePathName, userPathName)
+	cursor, err := NewBuilder(mySQL, &User{}).OrderBy("user_id", "asc").Cursor()
+	assert.NoError(t, err)
+
+	names := make([]string, 0)
+	err = cursor.Each(func(item interface{}) error {
+		names = append(names, item.(*User).Username)
+		if len(names) == 2 {
+			return fmt.Errorf("stop")
+		}
+
+		return nil
+	})
+	assert.EqualError(t, err, "stop")
+	assert.Equal(t, []string{"test1", "test2"}, names)
+
+	cursor, err = NewBuilder(mySQL, &User{}).Where("status", 1).Cursor()
+	assert.NoError(t, err)
+	defer cursor.Close()
+
+	total := 0
+	for cursor.Next() {
+		user := &User{}
+		assert.NoError(t, cursor.Scan(user))
+		total++
+	}
+
+	assert.NoError(t, cursor.Err())
+	assert.Equal(t, 3, total)
+}
+
+func TestBuilder_Clone(t *testing.T) {
+	base := NewBuilder(&MySQl{}, &User{}).Select("username").Where("status", 1).GroupBy("status")
+	list := base.Clone().Where("username", "test1").OrderBy("user_id", "desc").Limit(10)
+	count := base.Clone().Where("password", "v123456")
+
+	assert.Equal(t, "SELECT `username` FROM `user` WHERE `status` = ? GROUP BY `status`", base.String())
+	assert.Equal(t, "SELECT `username` FROM `user` WHERE `status` = ? AND `username` = ? GROUP BY `status` ORDER BY `user_id` DESC LIMIT 10", list.String())
+	assert.Equal(t, "SELECT `username` FROM `user` WHERE `status` = ? AND `password` = ? GROUP BY `status`", count.String())
+	assert.Equal(t, []interface{}{1}, base.bindings)
+	assert.Equal(t, []interface{}{1, "test1"}, list.bindings)
+
+	// 多次渲染结果一致，不修改查询对象
+	assert.Equal(t, base.String(), base.String())
+	assert.Equal(t, []string{"username"}, base.columns)
+	assert.Equal(t, []string{"status"}, base.groups)
+}
+
+func TestBuilder_When(t *testing.T) {
+	username, status := "test1", 0
+	s := NewBuilder(&MySQl{}, &User{}).
+		When(username != "", func(builder *Builder) {
+			builder.Where("username", username)
+		}).
+		When(status > 0, func(builder *Builder) {
+			builder.Where("status", status)
+		}).
+		String()
+	assert.Equal(t, "SELECT * FROM `user` WHERE `username` = ?", s)
+}
+
+func TestBuilder_Scopes(t *testing.T) {
+	active := func(builder *Builder) *Builder {
+		return builder.Where("status", 1)
+	}
+
+	createdBetween := func(start, end string) func(builder *Builder) *Builder {
+		return func(builder *Builder) *Builder {
+			return builder.Where("created_at", "between", start, end)
+		}
+	}
+
+	b := NewBuilder(&MySQl{}, &User{}).Scopes(active, createdBetween("2020-11-01", "2020-11-30"))
+	assert.Equal(t, "SELECT * FROM `user` WHERE `status` = ? AND `created_at` BETWEEN ? AND ?", b.String())
+	assert.Equal(t, []interface{}{1, "2020-11-01", "2020-11-30"}, b.bindings)
+}
+
+func TestBuilder_Err(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *Builder
+		err     string
+	}{
+		{
+			name:    "字段类型错误",
+			builder: NewBuilder(&MySQl{}, &User{}).Where(1),
+			err:     "where column must be a string or func(*Builder) *Builder, got int",
+		},
+		{
+			name:    "运算符类型错误",
+			builder: NewBuilder(&MySQl{}, &User{}).Where("status", 1, 2),
+			err:     "where status operator must be a string, got int",
+		},
+		{
+			name:    "运算符不支持",
+			builder: NewBuilder(&MySQl{}, &User{}).Where("status", "=;", 2),
+			err:     `where status has unsupported operator "=;"`,
+		},
+		{
+			name:    "参数数量错误",
+			builder: NewBuilder(&MySQl{}, &User{}).Where("status", ">", 1, 2),
+			err:     "where status > needs 1 value, got 2",
+		},
+		{
+			name:    "between 参数数量错误",
+			builder: NewBuilder(&MySQl{}, &User{}).Where("status", "between", 1),
+			err:     "where status BETWEEN needs 2 values, got 1",
+		},
+		{
+			name: "嵌套查询错误",
+			builder: NewBuilder(&MySQl{}, &User{}).Where(func(builder *Builder) *Builder {
+				return builder.Where("status", "like", 1, 2)
+			}),
+			err: "where status LIKE needs 1 value, got 2",
+		},
+		{
+			name:    "多个错误",
+			builder: NewBuilder(&MySQl{}, &User{}).Select(1).Limit(),
+			err:     "select column must be a string or []string, got int; limit needs 1 or 2 arguments, got 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.EqualError(t, tt.builder.Err(), tt.err)
+			assert.EqualError(t, tt.builder.One(), tt.err)
+			assert.EqualError(t, tt.builder.All(), tt.err)
+			_, err := tt.builder.Paginate(1, 10)
+			assert.EqualError(t, err, tt.err)
+			_, err = tt.builder.Update()
+			assert.EqualError(t, err, tt.err)
+			_, err = tt.builder.Delete()
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+
+	assert.NoError(t, NewBuilder(&MySQl{}, &User{}).Where("status", 1).Err())
+	assert.Equal(t, ErrMissingTable, NewBuilder(&MySQl{}, &struct{}{}).One())
+}
+
+func TestBuilder_OrderByDirection(t *testing.T) {
+	b := NewBuilder(&MySQl{}, &User{}).OrderBy("username", "desc; DROP TABLE user")
+	assert.EqualError(t, b.Err(), `order by username has invalid direction "DESC; DROP TABLE USER"`)
+	assert.Equal(t, "SELECT * FROM `user`", b.String())
+
+	b = NewBuilder(&MySQl{}, &User{}).OrderBy("username`; DROP TABLE user; `", "asc")
+	assert.Error(t, b.Err())
+	assert.Equal(t, "SELECT * FROM `user`", b.String())
+}
+
+func TestBuilder_OrderBySafe(t *testing.T) {
+	b := NewBuilder(&MySQl{}, &User{}).OrderBySafe("-created_at,username:asc,password,status desc;", []string{"created_at", "username", "status"})
+	assert.NoError(t, b.Err())
+	assert.Equal(t, "SELECT * FROM `user` ORDER BY `created_at` DESC, `username` ASC", b.String())
+}
+
+func TestBuilder_Identifier(t *testing.T) {
+	b := NewBuilder(&MySQl{}, &User{}).
+		Table("user; DROP TABLE user").
+		Select("username", "password FROM user;").
+		GroupBy("status)").
+		Join("user u ON 1=1 --", "u.user_id = user.user_id").
+		Where("status = 1 OR", 1).
+		WhereNull("deleted_at)").
+		WhereColumn("user_id", "=", "1;")
+	assert.Equal(t, 7, len(b.Err().(BuilderError)))
+	assert.Equal(t, "SELECT `username` FROM `user`", b.String())
+
+	s := NewBuilder(&MySQl{}, &User{}).Select("user.*", "*").String()
+	assert.Equal(t, "SELECT `user`.*, * FROM `user`", s)
+}
+
+func TestBuilder_ToSQL(t *testing.T) {
+	query, bindings, err := NewBuilder(&MySQl{}, &User{}).
+		Having("COUNT(*) > ?", 1).
+		Where("status", "in", []int{1, 2}).
+		Join("user as u", "u.user_id = user.user_id AND u.status = ?", 3).
+		GroupBy("username").
+		ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `user` JOIN `user` AS `u` ON (u.user_id = user.user_id AND u.status = ?) WHERE `status` IN (?, ?) GROUP BY `username` HAVING COUNT(*) > ?", query)
+	assert.Equal(t, []interface{}{3, 1, 2, 1}, bindings)
+
+	// 构建错误
+	_, _, err = NewBuilder(&MySQl{}, &User{}).Where(1).ToSQL()
+	assert.Error(t, err)
+
+	// IN 查询参数为空
+	_, _, err = NewBuilder(&MySQl{}, &User{}).Where("status", "in", []int{}).ToSQL()
+	assert.Error(t, err)
+}
+
+func TestBuilder_ToCountSQL(t *testing.T) {
+	query, bindings, err := NewBuilder(&MySQl{}, &User{}).Where("status", "in", 1, 2).OrderBy("user_id", "desc").ToCountSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) AS `total` FROM `user` WHERE `status` IN (?, ?)", query)
+	assert.Equal(t, []interface{}{1, 2}, bindings)
+}
+
+func TestBuilder_ToUpdateSQL(t *testing.T) {
+	query, bindings, err := NewBuilder(&MySQl{}, &User{Username: "test"}).Where("user_id", "in", []int64{1, 2}).ToUpdateSQL("status")
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE `user` SET `username` = ?,`status` = ? WHERE `user_id` IN (?, ?)", query)
+	assert.Equal(t, []interface{}{"test", 0, int64(1), int64(2)}, bindings)
+
+	_, _, err = NewBuilder(&MySQl{}, &User{}).Where("user_id", 1).ToUpdateSQL()
+	assert.EqualError(t, err, "update needs at least one column to set")
+
+	_, _, err = NewBuilder(&MySQl{}, &[]*User{}).Where("user_id", 1).ToUpdateSQL()
+	assert.EqualError(t, err, "data must be a non-nil pointer to struct, got *[]*mysql.User")
+}
+
+func TestBuilder_ToDeleteSQL(t *testing.T) {
+	query, bindings, err := NewBuilder(&MySQl{}, &User{}).Where("user_id", "in", []int64{1, 2}).ToDeleteSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM `user` WHERE `user_id` IN (?, ?)", query)
+	assert.Equal(t, []interface{}{int64(1), int64(2)}, bindings)
+
+	_, _, err = NewBuilder(&MySQl{}, &struct{}{}).ToDeleteSQL()
+	assert.Equal(t, ErrMissingTable, err)
+}
+
+func TestBuilder_ToInsertSQL(t *testing.T) {
+	query, bindings, err := NewBuilder(&MySQl{}, &User{Username: "test", Password: "123456"}).ToInsertSQL("status")
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `user` (`username`, `password`, `status`) VALUES (?, ?, ?)", query)
+	assert.Equal(t, []interface{}{"test", "123456", 0}, bindings)
+
+	_, _, err = NewBuilder(&MySQl{}, &User{}).ToInsertSQL()
+	assert.EqualError(t, err, "insert needs at least one column")
+}
+
+func TestBuilder_ToUpdateSQLWithJoin(t *testing.T) {
+	query, bindings, err := NewBuilder(&MySQl{}, &User{Status: 2}).
+		Table("user as u").
+		Join("user as p", "p.user_id = u.user_id AND p.status = ?", 1).
+		Where("p.username", "test1").
+		ToUpdateSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE `user` AS `u` JOIN `user` AS `p` ON (p.user_id = u.user_id AND p.status = ?) SET `u`.`status` = ? WHERE `p`.`username` = ?", query)
+	assert.Equal(t, []interface{}{1, 2, "test1"}, bindings)
+
+	query, bindings, err = NewBuilder(&MySQl{}, &User{Status: 2}).Where("status", 1).OrderBy("user_id", "asc").Limit(10).ToUpdateSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE `user` SET `status` = ? WHERE `status` = ? ORDER BY `user_id` ASC LIMIT 10", query)
+	assert.Equal(t, []interface{}{2, 1}, bindings)
+
+	_, _, err = NewBuilder(&MySQl{}, &User{Status: 2}).Join("user as p", "p.user_id = user.user_id").Limit(10).ToUpdateSQL()
+	assert.EqualError(t, err, "update with join does not support order by and limit")
+
+	_, _, err = NewBuilder(&MySQl{}, &User{Status: 2}).Limit(10, 10).ToUpdateSQL()
+	assert.EqualError(t, err, "update does not support offset")
+}
+
+func TestBuilder_ToDeleteSQLWithJoin(t *testing.T) {
+	query, bindings, err := NewBuilder(&MySQl{}, &User{}).
+		Join("user as p", "p.user_id = user.user_id").
+		Where("p.status", 2).
+		ToDeleteSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE `user` FROM `user` JOIN `user` AS `p` ON (p.user_id = user.user_id) WHERE `p`.`status` = ?", query)
+	assert.Equal(t, []interface{}{2}, bindings)
+
+	query, _, err = NewBuilder(&MySQl{}, &User{}).Where("status", 1).OrderBy("created_at", "asc").Limit(1000).ToDeleteSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM `user` WHERE `status` = ? ORDER BY `created_at` ASC LIMIT 1000", query)
+
+	_, _, err = NewBuilder(&MySQl{}, &User{}).Join("user as p", "p.user_id = user.user_id").OrderBy("user_id", "asc").ToDeleteSQL()
+	assert.EqualError(t, err, "delete with join does not support order by and limit")
+
+	_, _, err = NewBuilder(&MySQl{}, &User{}).Limit(10).Offset(10).ToDeleteSQL()
+	assert.EqualError(t, err, "delete does not support offset")
+}
+
+func TestBuilder_DeleteLimit(t *testing.T) {
+	mySQL := NewTestMySQL(t, examplePathName, userPathName)
+	num, err := mySQL.Builder(&User{}).Where("status", 1).OrderBy("user_id", "asc").Limit(2).Delete()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), num)
+
+	user := &User{}
+	assert.NoError(t, mySQL.Builder(user).One())
+	assert.Equal(t, int64(3), user.UserId)
+}
+
+func Test_tableAlias(t *testing.T) {
+	assert.Equal(t, "user", tableAlias("user"))
+	assert.Equal(t, "u", tableAlias("user as u"))
+	assert.Equal(t, "u", tableAlias("`user` AS `u`"))
+	assert.Equal(t, "u", tableAlias("user u"))
+}
+
+func TestBuilder_ToUpdateMapSQL(t *testing.T) {
+	query, bindings, err := NewBuilder(&MySQl{}, &User{}).
+		Where("user_id", 1).
+		ToUpdateMapSQL(map[string]interface{}{
+			"status":     0,
+			"username":   "test",
+			"updated_at": Raw("NOW()"),
+			"password":   Raw("CONCAT(`password`, ?)", "-1"),
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE `user` SET `password` = CONCAT(`password`, ?),`status` = ?,`updated_at` = NOW(),`username` = ? WHERE `user_id` = ?", query)
+	assert.Equal(t, []interface{}{"-1", 0, "test", 1}, bindings)
+
+	_, _, err = NewBuilder(&MySQl{}, &User{}).ToUpdateMapSQL(map[string]interface{}{"status = 1, password": ""})
+	assert.EqualError(t, err, `invalid identifier "status = 1, password"`)
+
+	_, _, err = NewBuilder(&MySQl{}, &User{}).ToUpdateMapSQL(nil)
+	assert.EqualError(t, err, "update needs at least one column to set")
+}
+
+func TestBuilder_incrementValues(t *testing.T) {
+	b := NewBuilder(&MySQl{}, &User{}).Where("user_id", 1)
+	query, bindings, err := b.ToUpdateMapSQL(b.incrementValues("status", "+", 2, []map[string]interface{}{{"username": "test"}}))
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE `user` SET `status` = `status` + ?,`username` = ? WHERE `user_id` = ?", query)
+	assert.Equal(t, []interface{}{2, "test", 1}, bindings)
+
+	// 关联修改时自增字段和表达式中的字段都使用主表的表名
+	b = NewBuilder(&MySQl{}, &User{}).Table("user as u").Join("user as o", "o.user_id = u.user_id")
+	query, _, err = b.ToUpdateMapSQL(b.incrementValues("status", "-", 1, []map[string]interface{}{{"o.username": "test"}}))
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE `user` AS `u` JOIN `user` AS `o` ON (o.user_id = u.user_id) SET `o`.`username` = ?,`u`.`status` = `u`.`status` - ?", query)
+}
+
+func TestBuilder_ToUpdateMapSQLWithJoin(t *testing.T) {
+	// 值中的表名不影响修改字段的表名
+	query, bindings, err := NewBuilder(&MySQl{}, &User{}).
+		Table("user as u").
+		Join("user as o", "o.user_id = u.user_id").
+		ToUpdateMapSQL(map[string]interface{}{"status": Raw("`o`.`status`"), "o.password": "test"})
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE `user` AS `u` JOIN `user` AS `o` ON (o.user_id = u.user_id) SET `o`.`password` = ?,`u`.`status` = `o`.`status`", query)
+	assert.Equal(t, []interface{}{"test"}, bindings)
+}
+
+func TestBuilder_Increment(t *testing.T) {
+	mySQL := NewTestMySQL(t, examplePathName, userPathName)
+	num, err := mySQL.Builder(&User{}).Where("user_id", 1).Increment("status", 2, map[string]interface{}{"password": ""})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), num)
+
+	num, err = mySQL.Builder(&User{}).Where("user_id", 2).Decrement("status", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), num)
+
+	user := make([]*User, 0)
+	assert.NoError(t, mySQL.Builder(&user).OrderBy("user_id", "asc").All())
+	assert.Equal(t, 3, user[0].Status)
+	assert.Equal(t, "", user[0].Password)
+	assert.Equal(t, 0, user[1].Status)
+}
+
+func TestBuilder_SoftDeletes(t *testing.T) {
+	s := NewBuilder(&MySQl{}, &SoftUser{}).Where("status", 1).OrWhere("status", 2).String()
+	assert.Equal(t, "SELECT * FROM `user` WHERE (`status` = ? OR `status` = ?) AND `deleted_at` IS NULL", s)
+
+	s = NewBuilder(&MySQl{}, &[]*SoftUser{}).String()
+	assert.Equal(t, "SELECT * FROM `user` WHERE `deleted_at` IS NULL", s)
+
+	s = NewBuilder(&MySQl{}, &SoftUser{}).Where("status", 1).WithTrashed().String()
+	assert.Equal(t, "SELECT * FROM `user` WHERE `status` = ?", s)
+
+	s = NewBuilder(&MySQl{}, &SoftUser{}).OnlyTrashed().Join("user as u", "u.user_id = user.user_id").String()
+	assert.Equal(t, "SELECT * FROM `user` JOIN `user` AS `u` ON (u.user_id = user.user_id) WHERE `user`.`deleted_at` IS NOT NULL", s)
+
+	query, bindings, err := NewBuilder(&MySQl{}, &SoftUser{}).Where("status", 1).ToDeleteSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE `user` SET `deleted_at` = ? WHERE (`status` = ?) AND `deleted_at` IS NULL", query)
+	assert.Equal(t, 2, len(bindings))
+
+	query, _, err = NewBuilder(&MySQl{}, &SoftUser{}).Where("status", 1).ToCountSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) AS `total` FROM `user` WHERE (`status` = ?) AND `deleted_at` IS NULL", query)
+
+	_, err = NewBuilder(&MySQl{}, &User{}).Restore()
+	assert.EqualError(t, err, "restore needs a model implementing SoftDeletes")
+}
+
+func TestBuilder_SoftDeletesQuery(t *testing.T) {
+	mySQL := NewTestMySQL(t, examplePathName, userPathName)
+	num, err := mySQL.Builder(&SoftUser{}).Where("user_id", 1).Delete()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), num)
+
+	users := make([]*SoftUser, 0)
+	assert.NoError(t, mySQL.Builder(&users).All())
+	assert.Equal(t, 2, len(users))
+
+	users = make([]*SoftUser, 0)
+	assert.NoError(t, mySQL.Builder(&users).WithTrashed().All())
+	assert.Equal(t, 3, len(users))
+
+	users = make([]*SoftUser, 0)
+	assert.NoError(t, mySQL.Builder(&users).OnlyTrashed().All())
+	assert.Equal(t, 1, len(users))
+	assert.Equal(t, int64(1), users[0].UserId)
+
+	num, err = mySQL.Builder(&SoftUser{}).Where("user_id", 1).Restore()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), num)
+
+	num, err = mySQL.Builder(&SoftUser{}).Where("user_id", 2).ForceDelete()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), num)
+
+	total, err := mySQL.Builder(&users).WithTrashed().Paginate(1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+}
@@ -2,25 +2,80 @@ package mysql
 
 import (
 	"reflect"
+	"strings"
 )
 
 type Column struct {
 	Name   string      `json:"name"`
 	Value  interface{} `json:"value"`
 	IsZero bool        `json:"is_zero"`
+
+	// 只读字段，新增和修改时忽略，例如：db:"name,readonly"
+	ReadOnly bool `json:"read_only"`
+
+	// 零值时忽略，指定需要处理零值的字段也会忽略，例如：db:"name,omitempty"
+	OmitEmpty bool `json:"omit_empty"`
+
+	// 只在新增时写入，修改时忽略，例如：db:"name,insertonly"
+	InsertOnly bool `json:"insert_only"`
 }
 
+// StructColumns 获取结构体的字段，会展开匿名嵌入的结构体，忽略 - 和未导出的字段
+// 没有设置 tag 的字段和 sqlx 一样使用小写的字段名称，结构体的字段信息会缓存
 func StructColumns(data interface{}, tagName string) []*Column {
-	v := reflect.ValueOf(data).Elem()
-	name := reflect.TypeOf(data).Elem()
-	columns := make([]*Column, 0)
-	for i, length := 0, v.NumField(); i < length; i++ {
+	value := reflect.ValueOf(data).Elem()
+	schema := getSchema(value.Type(), tagName)
+	columns := make([]*Column, 0, len(schema.columns))
+	for _, column := range schema.columns {
+		// nil 的嵌入结构体指针忽略
+		field, ok := fieldByIndex(value, column.index)
+		if !ok {
+			continue
+		}
+
 		columns = append(columns, &Column{
-			Name:   name.Field(i).Tag.Get(tagName),
-			Value:  v.Field(i).Interface(),
-			IsZero: v.Field(i).IsZero(),
+			Name:       column.name,
+			Value:      field.Interface(),
+			IsZero:     field.IsZero(),
+			ReadOnly:   column.readOnly,
+			OmitEmpty:  column.omitEmpty,
+			InsertOnly: column.insertOnly,
 		})
 	}
 
 	return columns
 }
+
+// parseColumnTag 解析字段 tag，例如：name,readonly,omitempty
+func parseColumnTag(tag string) (string, map[string]bool) {
+	parts := strings.Split(tag, ",")
+	options := make(map[string]bool, len(parts)-1)
+	for _, option := range parts[1:] {
+		options[strings.TrimSpace(option)] = true
+	}
+
+	return strings.TrimSpace(parts[0]), options
+}
+
+// insertable 新增数据时是否写入，zeroColumn 为需要写入零值的字段
+func (c *Column) insertable(zeroColumn []string) bool {
+	return !c.ReadOnly && c.writable(zeroColumn)
+}
+
+// updatable 修改数据时是否写入，zeroColumn 为需要写入零值的字段
+func (c *Column) updatable(zeroColumn []string) bool {
+	return !c.ReadOnly && !c.InsertOnly && c.writable(zeroColumn)
+}
+
+func (c *Column) writable(zeroColumn []string) bool {
+	return !c.IsZero || (!c.OmitEmpty && InStringSlice(zeroColumn, c.Name))
+}
+
+// isStructType 是否为结构体或者结构体指针
+func isStructType(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	return typ.Kind() == reflect.Struct
+}
@@ -12,3 +12,58 @@ func TestStructColumns(t *testing.T) {
 	fmt.Printf("%#v\n", user)
 	assert.Equal(t, 6, len(user))
 }
+
+type baseModel struct {
+	Id        int64 `db:"id"`
+	CreatedAt Time  `db:"created_at,readonly"`
+}
+
+type Audit struct {
+	CreatedBy string `db:"created_by,insertonly"`
+}
+
+type Article struct {
+	baseModel
+	*Audit
+	Title   string `db:"title"`
+	Summary string `db:"summary,omitempty"`
+	Views   int
+	Tags    []string `db:"-"`
+	secret  string
+}
+
+func TestStructColumns_Embedded(t *testing.T) {
+	article := &Article{baseModel: baseModel{Id: 1}, Audit: &Audit{CreatedBy: "admin"}, Title: "title", secret: "secret"}
+	columns := StructColumns(article, "db")
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		names = append(names, column.Name)
+	}
+
+	assert.Equal(t, []string{"id", "created_at", "created_by", "title", "summary", "views"}, names)
+	assert.Equal(t, int64(1), columns[0].Value)
+	assert.True(t, columns[1].ReadOnly)
+	assert.True(t, columns[2].InsertOnly)
+	assert.True(t, columns[4].OmitEmpty)
+
+	// nil 的嵌入结构体指针忽略
+	assert.Equal(t, 5, len(StructColumns(&Article{}, "db")))
+}
+
+func TestColumn_Writable(t *testing.T) {
+	readonly := &Column{Name: "created_at", ReadOnly: true, Value: 1}
+	assert.False(t, readonly.insertable(nil))
+	assert.False(t, readonly.updatable(nil))
+
+	insertOnly := &Column{Name: "created_by", InsertOnly: true, Value: "admin"}
+	assert.True(t, insertOnly.insertable(nil))
+	assert.False(t, insertOnly.updatable(nil))
+
+	omitEmpty := &Column{Name: "summary", OmitEmpty: true, IsZero: true}
+	assert.False(t, omitEmpty.insertable([]string{"summary"}))
+	assert.False(t, omitEmpty.updatable([]string{"summary"}))
+
+	zero := &Column{Name: "status", IsZero: true}
+	assert.False(t, zero.updatable(nil))
+	assert.True(t, zero.updatable([]string{"status"}))
+}
@@ -0,0 +1,72 @@
+package mysql
+
+import (
+	"reflect"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Cursor 逐行读取查询结果，避免一次性将全部数据加载到内存
+type Cursor struct {
+	db *MySQl
+
+	rows *sqlx.Rows
+
+	// 每行数据对应的结构体类型
+	typ reflect.Type
+}
+
+// Next 是否还有下一行数据
+func (c *Cursor) Next() bool {
+	return c.rows.Next()
+}
+
+// Scan 将当前行数据写入 dest
+func (c *Cursor) Scan(dest interface{}) error {
+	if err := c.rows.StructScan(dest); err != nil {
+		return err
+	}
+
+	return found(c.db, dest)
+}
+
+// Each 逐行读取数据并执行回调，item 为新创建的结构体指针，回调返回错误时停止读取
+func (c *Cursor) Each(fn func(item interface{}) error) error {
+	defer c.Close()
+	for c.rows.Next() {
+		item := reflect.New(c.typ).Interface()
+		if err := c.rows.StructScan(item); err != nil {
+			return err
+		}
+
+		if err := found(c.db, item); err != nil {
+			return err
+		}
+
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+
+	return c.rows.Err()
+}
+
+// Err 读取过程中的错误
+func (c *Cursor) Err() error {
+	return c.rows.Err()
+}
+
+// Close 关闭游标，释放连接
+func (c *Cursor) Close() error {
+	return c.rows.Close()
+}
+
+// structType 获取数据对应的结构体类型，支持 *T、*[]T 和 *[]*T
+func structType(data interface{}) reflect.Type {
+	t := reflect.TypeOf(data)
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+
+	return t
+}
@@ -0,0 +1,15 @@
+package mysql
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_structType(t *testing.T) {
+	userType := reflect.TypeOf(User{})
+	assert.Equal(t, userType, structType(&User{}))
+	assert.Equal(t, userType, structType(&[]User{}))
+	assert.Equal(t, userType, structType(&[]*User{}))
+}
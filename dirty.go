@@ -0,0 +1,179 @@
+package mysql
+
+import (
+	"reflect"
+)
+
+// Original 记录模型查询、新增、修改后的字段原始值，用于判断修改了哪些字段，嵌入到模型中使用，例如：
+//
+//	type User struct {
+//		mysql.Original
+//		UserId int64 `db:"user_id"`
+//	}
+type Original struct {
+	original map[string]interface{}
+}
+
+func (o *Original) setOriginal(values map[string]interface{}) {
+	o.original = values
+}
+
+func (o *Original) getOriginal() map[string]interface{} {
+	return o.original
+}
+
+// tracker 嵌入了 Original 的模型
+type tracker interface {
+	setOriginal(values map[string]interface{})
+	getOriginal() map[string]interface{}
+}
+
+// SyncOriginal 使用模型当前的值作为原始值，模型没有嵌入 Original 时不处理
+// 指针、slice、map 的值会复制一份，直接修改指向的数据也能判断为修改过
+func SyncOriginal(model Model) {
+	if t, ok := model.(tracker); ok {
+		columns := StructColumns(model, "db")
+		values := make(map[string]interface{}, len(columns))
+		for _, column := range columns {
+			values[column.Name] = copyValue(column.Value)
+		}
+
+		t.setOriginal(values)
+	}
+}
+
+// copyValue 深度复制指针、slice、map 的值，其他类型直接返回
+func copyValue(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	return deepCopy(reflect.ValueOf(value)).Interface()
+}
+
+func deepCopy(value reflect.Value) reflect.Value {
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return value
+		}
+
+		clone := reflect.New(value.Type().Elem())
+		clone.Elem().Set(deepCopy(value.Elem()))
+		return clone
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+
+		clone := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		reflect.Copy(clone, value)
+		if isReferenceKind(value.Type().Elem().Kind()) {
+			for i, length := 0, value.Len(); i < length; i++ {
+				clone.Index(i).Set(deepCopy(value.Index(i)))
+			}
+		}
+
+		return clone
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+
+		clone := reflect.MakeMapWithSize(value.Type(), value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			clone.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+		}
+
+		return clone
+	}
+
+	return value
+}
+
+func isReferenceKind(kind reflect.Kind) bool {
+	return kind == reflect.Ptr || kind == reflect.Slice || kind == reflect.Map
+}
+
+// GetOriginal 获取字段的原始值，没有记录原始值时返回 nil
+func GetOriginal(model Model, column string) interface{} {
+	if t, ok := model.(tracker); ok {
+		return t.getOriginal()[column]
+	}
+
+	return nil
+}
+
+// GetChanges 获取和原始值不一样的字段和当前值，没有记录原始值时返回全部字段
+func GetChanges(model Model) map[string]interface{} {
+	var original map[string]interface{}
+	if t, ok := model.(tracker); ok {
+		original = t.getOriginal()
+	}
+
+	changes := make(map[string]interface{})
+	for _, column := range StructColumns(model, "db") {
+		if value, ok := original[column.Name]; ok && reflect.DeepEqual(value, column.Value) {
+			continue
+		}
+
+		changes[column.Name] = column.Value
+	}
+
+	return changes
+}
+
+// IsDirty 字段是否修改过，没有指定字段时判断是否有任意字段修改过
+func IsDirty(model Model, columns ...string) bool {
+	changes := GetChanges(model)
+	if len(columns) == 0 {
+		return len(changes) > 0
+	}
+
+	for _, column := range columns {
+		if _, ok := changes[column]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// found 查询数据后记录原始值并执行 AfterFind，data 可以是结构体指针或者 slice 指针
+func found(db *MySQl, data interface{}) error {
+	syncOriginals(data)
+	return afterFind(db, data)
+}
+
+// syncOriginals 记录查询结果的原始值，data 可以是结构体指针或者 slice 指针
+func syncOriginals(data interface{}) {
+	if model, ok := data.(Model); ok {
+		SyncOriginal(model)
+		return
+	}
+
+	value := reflect.ValueOf(data)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Slice {
+		return
+	}
+
+	// 元素没有嵌入 Original 时不需要遍历
+	elemType := value.Elem().Type().Elem()
+	trackerType := reflect.TypeOf((*tracker)(nil)).Elem()
+	if !elemType.Implements(trackerType) && !reflect.PtrTo(elemType).Implements(trackerType) {
+		return
+	}
+
+	slice := value.Elem()
+	for i, length := 0, slice.Len(); i < length; i++ {
+		item := slice.Index(i)
+		if item.Kind() != reflect.Ptr {
+			item = item.Addr()
+		}
+
+		if model, ok := item.Interface().(Model); ok && !item.IsNil() {
+			SyncOriginal(model)
+		}
+	}
+}
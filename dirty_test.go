@@ -0,0 +1,174 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type DirtyUser struct {
+	Original
+	UserId    int64  `db:"user_id" json:"user_id"`
+	Username  string `db:"username" json:"username"`
+	Password  string `db:"password" json:"password"`
+	Status    int    `db:"status" json:"status"`
+	CreatedAt Time   `db:"created_at" json:"created_at"`
+	UpdatedAt Time   `db:"updated_at" json:"updated_at"`
+}
+
+func (*DirtyUser) TableName() string {
+	return "user"
+}
+
+func (*DirtyUser) PK() string {
+	return "user_id"
+}
+
+func (*DirtyUser) TimestampsValue() interface{} {
+	return Time(time.Now())
+}
+
+func TestGetChanges(t *testing.T) {
+	user := &DirtyUser{UserId: 1, Username: "test", Status: 1}
+
+	// 没有原始值时全部字段都修改过
+	assert.Equal(t, 6, len(GetChanges(user)))
+	assert.True(t, IsDirty(user))
+
+	SyncOriginal(user)
+	assert.Equal(t, map[string]interface{}{}, GetChanges(user))
+	assert.False(t, IsDirty(user))
+	assert.Equal(t, "test", GetOriginal(user, "username"))
+
+	// 修改为零值也是修改
+	user.Status, user.Password = 0, "123456"
+	assert.Equal(t, map[string]interface{}{"status": 0, "password": "123456"}, GetChanges(user))
+	assert.True(t, IsDirty(user, "status"))
+	assert.True(t, IsDirty(user, "username", "password"))
+	assert.False(t, IsDirty(user, "username"))
+
+	// 没有嵌入 Original
+	assert.Nil(t, GetOriginal(&User{}, "username"))
+	assert.True(t, IsDirty(&User{}))
+}
+
+type ProfileUser struct {
+	Original
+	UserId   int64             `db:"user_id"`
+	Nickname *string           `db:"nickname"`
+	Data     []byte            `db:"data"`
+	Tags     []*string         `db:"tags"`
+	Meta     map[string]string `db:"meta"`
+}
+
+func (*ProfileUser) TableName() string {
+	return "user"
+}
+
+func (*ProfileUser) PK() string {
+	return "user_id"
+}
+
+func TestSyncOriginal_Copy(t *testing.T) {
+	nickname, tag := "a", "go"
+	user := &ProfileUser{
+		UserId:   1,
+		Nickname: &nickname,
+		Data:     []byte("abc"),
+		Tags:     []*string{&tag},
+		Meta:     map[string]string{"key": "value"},
+	}
+
+	SyncOriginal(user)
+	assert.False(t, IsDirty(user))
+
+	// 直接修改指向的数据
+	*user.Nickname = "b"
+	assert.True(t, IsDirty(user, "nickname"))
+	assert.Equal(t, "a", *GetOriginal(user, "nickname").(*string))
+
+	user.Data[0] = 'z'
+	assert.True(t, IsDirty(user, "data"))
+	assert.Equal(t, []byte("abc"), GetOriginal(user, "data"))
+
+	*user.Tags[0] = "mysql"
+	assert.True(t, IsDirty(user, "tags"))
+
+	user.Meta["key"] = "changed"
+	assert.True(t, IsDirty(user, "meta"))
+	assert.Equal(t, []string{"data", "meta", "nickname", "tags"}, mapKeys(GetChanges(user)))
+
+	// nil 值不复制
+	empty := &ProfileUser{}
+	SyncOriginal(empty)
+	assert.False(t, IsDirty(empty))
+	assert.Nil(t, GetOriginal(empty, "nickname"))
+}
+
+func TestSyncOriginals(t *testing.T) {
+	users := []*DirtyUser{{UserId: 1}, nil, {UserId: 2}}
+	syncOriginals(&users)
+	assert.False(t, IsDirty(users[0]))
+	assert.False(t, IsDirty(users[2]))
+
+	values := []DirtyUser{{UserId: 1}}
+	syncOriginals(&values)
+	assert.False(t, IsDirty(&values[0]))
+
+	user := &DirtyUser{UserId: 1}
+	syncOriginals(user)
+	assert.False(t, IsDirty(user))
+
+	// 没有嵌入 Original 不处理
+	syncOriginals(&[]*User{{}})
+}
+
+func TestMySQl_UpdateDirty(t *testing.T) {
+	mySQL := NewTestMySQL(t, examplePathName, userPathName)
+
+	user := &DirtyUser{UserId: 1}
+	assert.NoError(t, mySQL.Find(user))
+	assert.False(t, IsDirty(user))
+
+	// 没有修改不执行
+	row, err := mySQL.UpdateDirty(user)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), row)
+
+	// 其他地方修改了密码，只修改状态不会覆盖
+	_, err = mySQL.Exec("UPDATE `user` SET `password` = ? WHERE `user_id` = ?", "changed", 1)
+	assert.NoError(t, err)
+
+	user.Status = 0
+	row, err = mySQL.UpdateDirty(user)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), row)
+	assert.False(t, IsDirty(user))
+
+	found := &DirtyUser{UserId: 1}
+	assert.NoError(t, mySQL.Find(found))
+	assert.Equal(t, 0, found.Status)
+	assert.Equal(t, "changed", found.Password)
+
+	users := make([]*DirtyUser, 0)
+	assert.NoError(t, mySQL.Builder(&users).All())
+	assert.False(t, IsDirty(users[0]))
+
+	created := &DirtyUser{Username: "dirty", Password: "123456"}
+	assert.NoError(t, mySQL.Create(created))
+	assert.False(t, IsDirty(created))
+
+	_, err = mySQL.UpdateDirty(&User{UserId: 1})
+	assert.Error(t, err)
+}
+
+func TestMySQl_UpdateDirtyErr(t *testing.T) {
+	mySQL := &MySQl{}
+	_, err := mySQL.UpdateDirty(&User{UserId: 1})
+	assert.EqualError(t, err, "update dirty needs *mysql.User to embed Original")
+
+	// 没有记录原始值时不修改全部字段
+	_, err = mySQL.UpdateDirty(&DirtyUser{UserId: 1, Status: 1})
+	assert.EqualError(t, err, "update dirty needs the original values of *mysql.DirtyUser, use Find or SyncOriginal first")
+}
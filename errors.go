@@ -0,0 +1,24 @@
+package mysql
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrMissingTable 查询没有指定表名
+var ErrMissingTable = errors.New("missing table name, use Table() or pass a Model")
+
+// ErrStaleObject 乐观锁修改失败，数据已经被修改或者删除
+var ErrStaleObject = errors.New("stale object, the row has been modified or deleted")
+
+// BuilderError 构建查询过程中记录的错误
+type BuilderError []error
+
+func (e BuilderError) Error() string {
+	messages := make([]string, len(e))
+	for k, err := range e {
+		messages[k] = err.Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
@@ -0,0 +1,24 @@
+package mysql
+
+// Expression 原生SQL表达式，不会被当作字段名称或者绑定参数处理
+type Expression struct {
+	SQL  string
+	Args []interface{}
+
+	// 创建表达式时的错误
+	err error
+}
+
+// Raw 创建原生SQL表达式，例如：Raw("`balance` - ?", 10)
+func Raw(sql string, args ...interface{}) Expression {
+	return Expression{SQL: sql, Args: args}
+}
+
+func (e Expression) String() string {
+	return e.SQL
+}
+
+// Err 创建表达式时的错误
+func (e Expression) Err() error {
+	return e.err
+}
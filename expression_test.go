@@ -0,0 +1,13 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRaw(t *testing.T) {
+	e := Raw("`balance` - ?", 10)
+	assert.Equal(t, Expression{SQL: "`balance` - ?", Args: []interface{}{10}}, e)
+	assert.Equal(t, "`balance` - ?", e.String())
+}
@@ -0,0 +1,187 @@
+package mysql
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// FilterTagName 查询条件结构体使用的标签名称，例如：`query:"username,like"`
+const FilterTagName = "query"
+
+// filterOperators 标签中支持的查询方式
+var filterOperators = map[string]string{
+	"":        "=",
+	"eq":      "=",
+	"=":       "=",
+	"ne":      "!=",
+	"!=":      "!=",
+	"<>":      "!=",
+	"gt":      ">",
+	">":       ">",
+	"gte":     ">=",
+	">=":      ">=",
+	"lt":      "<",
+	"<":       "<",
+	"lte":     "<=",
+	"<=":      "<=",
+	"like":    "LIKE",
+	"in":      "IN",
+	"notin":   "NOT IN",
+	"not in":  "NOT IN",
+	"between": "BETWEEN",
+	"null":    "IS NULL",
+	"notnull": "IS NOT NULL",
+}
+
+// WhereStruct 使用结构体添加查询条件，字段通过 query 标签指定字段名称和查询方式，零值字段不会添加查询条件
+//
+//	type UserFilter struct {
+//	    Username  string    `query:"username,like"`
+//	    Status    []int     `query:"status,in"`
+//	    CreatedAt []string  `query:"created_at,between"`
+//	    Deleted   bool      `query:"deleted_at,null"`
+//	}
+func (b *Builder) WhereStruct(filter interface{}) *Builder {
+	value := reflect.Indirect(reflect.ValueOf(filter))
+	if value.Kind() != reflect.Struct {
+		return b
+	}
+
+	return b.whereStruct(value)
+}
+
+func (b *Builder) whereStruct(value reflect.Value) *Builder {
+	typeOf := value.Type()
+	for i, length := 0, value.NumField(); i < length; i++ {
+		field, fieldValue := typeOf.Field(i), value.Field(i)
+
+		// 匿名嵌套结构体
+		if field.Anonymous && reflect.Indirect(fieldValue).Kind() == reflect.Struct {
+			if fieldValue.Kind() != reflect.Ptr || !fieldValue.IsNil() {
+				b.whereStruct(reflect.Indirect(fieldValue))
+			}
+
+			continue
+		}
+
+		tag := field.Tag.Get(FilterTagName)
+		if tag == "" || tag == "-" || field.PkgPath != "" {
+			continue
+		}
+
+		// 指针类型只有 nil 才忽略，可以使用指针查询零值
+		if fieldValue.Kind() == reflect.Ptr {
+			if fieldValue.IsNil() {
+				continue
+			}
+
+			fieldValue = fieldValue.Elem()
+		} else if isEmptyValue(fieldValue) {
+			continue
+		}
+
+		column, operator := parseFilterKey(tag)
+		b.whereOperator(column, operator, fieldValue.Interface())
+	}
+
+	return b
+}
+
+// WhereMap 使用 map 添加查询条件，key 可以指定查询方式，例如：map[string]interface{}{"username,like": "test", "status": []int{1, 2}}
+// 值为 nil 时查询 IS NULL，值为 slice 时查询 IN
+func (b *Builder) WhereMap(filter map[string]interface{}) *Builder {
+	keys := make([]string, 0, len(filter))
+	for key := range filter {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		column, operator := parseFilterKey(key)
+		value := filter[key]
+		if value == nil && (operator == "" || operator == "eq" || operator == "=") {
+			operator = "null"
+			value = true
+		}
+
+		b.whereOperator(column, operator, value)
+	}
+
+	return b
+}
+
+// whereOperator 按照查询方式添加查询条件
+func (b *Builder) whereOperator(column, operator string, value interface{}) *Builder {
+	op, ok := filterOperators[strings.ToLower(operator)]
+	if !ok {
+		return b.addError(fmt.Errorf("filter %s has unsupported operator %q", column, operator))
+	}
+
+	switch op {
+	case "IS NULL", "IS NOT NULL":
+		if isTrue, ok := value.(bool); ok && !isTrue {
+			return b
+		}
+
+		return b.whereNull("AND", column, op == "IS NOT NULL")
+	case "LIKE":
+		return b.WhereLike(column, fmt.Sprintf("%v", value))
+	case "IN", "NOT IN":
+		return b.whereIn("AND", column, value, op == "NOT IN")
+	case "BETWEEN":
+		values := toInterfaceSlice(value)
+		if len(values) != 2 {
+			return b.addError(fmt.Errorf("filter %s between needs 2 values, got %d", column, len(values)))
+		}
+
+		return b.WhereBetween(column, values[0], values[1])
+	}
+
+	// slice 使用 = 查询时为 IN，!= 查询时为 NOT IN
+	if isListValue(value) {
+		switch op {
+		case "=", "!=":
+			return b.whereIn("AND", column, value, op == "!=")
+		}
+
+		return b.addError(fmt.Errorf("filter %s operator %q does not support slice value", column, operator))
+	}
+
+	if op == "=" {
+		return b.Where(column, value)
+	}
+
+	return b.Where(column, op, value)
+}
+
+// isListValue 是否为 slice 或者数组，[]byte 作为单个值处理
+func isListValue(value interface{}) bool {
+	if _, ok := value.([]byte); ok {
+		return false
+	}
+
+	kind := reflect.ValueOf(value).Kind()
+	return kind == reflect.Slice || kind == reflect.Array
+}
+
+// parseFilterKey 解析字段名称和查询方式，例如：username,like
+func parseFilterKey(key string) (string, string) {
+	items := strings.SplitN(key, ",", 2)
+	if len(items) == 1 {
+		return strings.TrimSpace(items[0]), ""
+	}
+
+	return strings.TrimSpace(items[0]), strings.TrimSpace(items[1])
+}
+
+// isEmptyValue 是否为零值，空的 slice 和 map 也视为零值
+func isEmptyValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return value.Len() == 0
+	}
+
+	return value.IsZero()
+}
@@ -0,0 +1,85 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type userFilterBase struct {
+	Status []int `query:"status,in"`
+}
+
+type userFilter struct {
+	userFilterBase
+	UserId    *int64   `query:"user_id"`
+	Username  string   `query:"username,like"`
+	Password  string   `query:"-"`
+	CreatedAt []string `query:"created_at,between"`
+	UpdatedAt string   `query:"updated_at,gte"`
+	Deleted   bool     `query:"deleted_at,null"`
+	Keyword   string
+}
+
+func TestBuilder_WhereStruct(t *testing.T) {
+	var userId int64
+	b := NewBuilder(&MySQl{}, &User{}).WhereStruct(&userFilter{
+		userFilterBase: userFilterBase{Status: []int{1, 2}},
+		UserId:         &userId,
+		Username:       "test",
+		Password:       "123456",
+		CreatedAt:      []string{"2020-11-01", "2020-11-30"},
+		Deleted:        true,
+		Keyword:        "test",
+	})
+
+	assert.Equal(t, "SELECT * FROM `user` WHERE `status` IN (?) AND `user_id` = ? AND `username` LIKE ? AND `created_at` BETWEEN ? AND ? AND `deleted_at` IS NULL", b.String())
+	assert.Equal(t, []interface{}{[]int{1, 2}, int64(0), "%test%", "2020-11-01", "2020-11-30"}, b.bindings)
+
+	// 零值不添加查询条件
+	b = NewBuilder(&MySQl{}, &User{}).WhereStruct(userFilter{UpdatedAt: "2020-11-01"})
+	assert.Equal(t, "SELECT * FROM `user` WHERE `updated_at` >= ?", b.String())
+
+	// slice 没有指定查询方式时使用 IN
+	b = NewBuilder(&MySQl{}, &User{}).WhereStruct(struct {
+		Status   []int    `query:"status"`
+		Username []string `query:"username,ne"`
+	}{Status: []int{1, 2}, Username: []string{"a", "b"}})
+	assert.Equal(t, "SELECT * FROM `user` WHERE `status` IN (?) AND `username` NOT IN (?)", b.String())
+	assert.Equal(t, []interface{}{[]int{1, 2}, []string{"a", "b"}}, b.bindings)
+}
+
+func TestBuilder_WhereMap(t *testing.T) {
+	b := NewBuilder(&MySQl{}, &User{}).WhereMap(map[string]interface{}{
+		"username,like":      "test",
+		"status":             []int{1, 2},
+		"user_id,gt":         10,
+		"deleted_at":         nil,
+		"created_at,between": []string{"2020-11-01", "2020-11-30"},
+		"password":           "123456",
+	})
+
+	assert.Equal(t, "SELECT * FROM `user` WHERE `created_at` BETWEEN ? AND ? AND `deleted_at` IS NULL AND `password` = ? AND `status` IN (?) AND `user_id` > ? AND `username` LIKE ?", b.String())
+	assert.Equal(t, []interface{}{"2020-11-01", "2020-11-30", "123456", []int{1, 2}, 10, "%test%"}, b.bindings)
+}
+
+func Test_parseFilterKey(t *testing.T) {
+	column, operator := parseFilterKey("username")
+	assert.Equal(t, "username", column)
+	assert.Equal(t, "", operator)
+
+	column, operator = parseFilterKey("created_at, gte")
+	assert.Equal(t, "created_at", column)
+	assert.Equal(t, "gte", operator)
+}
+
+func TestBuilder_WhereMapErr(t *testing.T) {
+	b := NewBuilder(&MySQl{}, &User{}).WhereMap(map[string]interface{}{"status,unknown": 1})
+	assert.EqualError(t, b.Err(), `filter status has unsupported operator "unknown"`)
+
+	b = NewBuilder(&MySQl{}, &User{}).WhereMap(map[string]interface{}{"status,between": []int{1}})
+	assert.EqualError(t, b.Err(), "filter status between needs 2 values, got 1")
+
+	b = NewBuilder(&MySQl{}, &User{}).WhereMap(map[string]interface{}{"status,gt": []int{1, 2}})
+	assert.EqualError(t, b.Err(), `filter status operator "gt" does not support slice value`)
+}
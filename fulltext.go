@@ -0,0 +1,101 @@
+package mysql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// FullTextNatural 自然语言模式
+	FullTextNatural = "natural"
+
+	// FullTextBoolean 布尔模式，支持 +、-、* 等操作符
+	FullTextBoolean = "boolean"
+
+	// FullTextQueryExpansion 查询扩展模式
+	FullTextQueryExpansion = "expansion"
+)
+
+// fullTextModes 全文搜索模式对应的SQL
+var fullTextModes = map[string]string{
+	"":                     " IN NATURAL LANGUAGE MODE",
+	FullTextNatural:        " IN NATURAL LANGUAGE MODE",
+	FullTextBoolean:        " IN BOOLEAN MODE",
+	FullTextQueryExpansion: " WITH QUERY EXPANSION",
+}
+
+// WhereFullText 全文搜索，columns 需要建立 FULLTEXT 索引，mode 为空时使用自然语言模式
+func (b *Builder) WhereFullText(columns []string, query string, mode string) *Builder {
+	return b.whereFullText("AND", columns, query, mode)
+}
+
+// OrWhereFullText 或者全文搜索
+func (b *Builder) OrWhereFullText(columns []string, query string, mode string) *Builder {
+	return b.whereFullText("OR", columns, query, mode)
+}
+
+// SelectRelevance 查询最后一个全文搜索的相关度，alias 为相关度字段的别名
+func (b *Builder) SelectRelevance(alias string) *Builder {
+	if b.fullText == nil {
+		return b.addError(errors.New("select relevance needs WhereFullText"))
+	}
+
+	if !b.identifier(alias) {
+		return b
+	}
+
+	b.selects = append(b.selects, fmt.Sprintf("%s AS %s", b.fullText.SQL, b.warp(alias)))
+	b.selectBindings = append(b.selectBindings, b.fullText.Args...)
+	b.relevance = alias
+	return b
+}
+
+// OrderByRelevance 按照最后一个全文搜索的相关度排序
+func (b *Builder) OrderByRelevance(direction string) *Builder {
+	if b.fullText == nil {
+		return b.addError(errors.New("order by relevance needs WhereFullText"))
+	}
+
+	direction = strings.ToUpper(strings.TrimSpace(direction))
+	if direction != "ASC" && direction != "DESC" {
+		return b.addError(fmt.Errorf("order by relevance has invalid direction %q", direction))
+	}
+
+	// 已经查询了相关度，直接使用别名排序
+	if b.relevance != "" {
+		b.orders = append(b.orders, fmt.Sprintf("%s %s", b.warp(b.relevance), direction))
+		return b
+	}
+
+	b.orders = append(b.orders, fmt.Sprintf("%s %s", b.fullText.SQL, direction))
+	b.orderBindings = append(b.orderBindings, b.fullText.Args...)
+	return b
+}
+
+func (b *Builder) whereFullText(boolean string, columns []string, query string, mode string) *Builder {
+	modeSQL, ok := fullTextModes[strings.ToLower(mode)]
+	if !ok {
+		return b.addError(fmt.Errorf("full text has unsupported mode %q", mode))
+	}
+
+	if len(columns) == 0 {
+		return b.addError(errors.New("full text needs at least one column"))
+	}
+
+	fields := make([]string, len(columns))
+	for k, column := range columns {
+		if !b.identifier(column) {
+			return b
+		}
+
+		fields[k] = b.warp(column)
+	}
+
+	match := Raw(fmt.Sprintf("MATCH (%s) AGAINST (?%s)", strings.Join(fields, ", "), modeSQL), query)
+	b.fullText = &match
+	b.relevance = ""
+	b.wheres = append(b.wheres, fmt.Sprintf("%s %s", boolean, match.SQL))
+	b.bindings = append(b.bindings, match.Args...)
+	return b
+}
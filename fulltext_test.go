@@ -0,0 +1,55 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder_WhereFullText(t *testing.T) {
+	query, bindings, err := NewBuilder(&MySQl{}, &User{}).
+		Where("status", 1).
+		WhereFullText([]string{"username", "password"}, "test", FullTextNatural).
+		OrWhereFullText([]string{"username"}, "+test -admin", FullTextBoolean).
+		WhereFullText([]string{"password"}, "test", FullTextQueryExpansion).
+		ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `user` WHERE `status` = ? AND MATCH (`username`, `password`) AGAINST (? IN NATURAL LANGUAGE MODE) OR MATCH (`username`) AGAINST (? IN BOOLEAN MODE) AND MATCH (`password`) AGAINST (? WITH QUERY EXPANSION)", query)
+	assert.Equal(t, []interface{}{1, "test", "+test -admin", "test"}, bindings)
+
+	b := NewBuilder(&MySQl{}, &User{}).WhereFullText([]string{"username"}, "test", "fuzzy")
+	assert.EqualError(t, b.Err(), `full text has unsupported mode "fuzzy"`)
+
+	b = NewBuilder(&MySQl{}, &User{}).WhereFullText(nil, "test", "")
+	assert.EqualError(t, b.Err(), "full text needs at least one column")
+}
+
+func TestBuilder_SelectRelevance(t *testing.T) {
+	query, bindings, err := NewBuilder(&MySQl{}, &User{}).
+		Join("user as u", "u.user_id = user.user_id AND u.status = ?", 1).
+		WhereFullText([]string{"user.username"}, "test", "").
+		SelectRelevance("score").
+		OrderByRelevance("desc").
+		ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT *, MATCH (`user`.`username`) AGAINST (? IN NATURAL LANGUAGE MODE) AS `score` FROM `user` JOIN `user` AS `u` ON (u.user_id = user.user_id AND u.status = ?) WHERE MATCH (`user`.`username`) AGAINST (? IN NATURAL LANGUAGE MODE) ORDER BY `score` DESC", query)
+	assert.Equal(t, []interface{}{"test", 1, "test"}, bindings)
+
+	b := NewBuilder(&MySQl{}, &User{}).SelectRelevance("score").OrderByRelevance("desc")
+	assert.EqualError(t, b.Err(), "select relevance needs WhereFullText; order by relevance needs WhereFullText")
+}
+
+func TestBuilder_OrderByRelevance(t *testing.T) {
+	query, bindings, err := NewBuilder(&MySQl{}, &User{}).
+		Select("user_id").
+		WhereFullText([]string{"username"}, "test", FullTextBoolean).
+		OrderByRelevance("desc").
+		Limit(10).
+		ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT `user_id` FROM `user` WHERE MATCH (`username`) AGAINST (? IN BOOLEAN MODE) ORDER BY MATCH (`username`) AGAINST (? IN BOOLEAN MODE) DESC LIMIT 10", query)
+	assert.Equal(t, []interface{}{"test", "test"}, bindings)
+
+	b := NewBuilder(&MySQl{}, &User{}).WhereFullText([]string{"username"}, "test", "").OrderByRelevance("up")
+	assert.Error(t, b.Err())
+}
@@ -0,0 +1,125 @@
+package mysql
+
+import (
+	"reflect"
+)
+
+// BeforeCreate 创建数据前执行，返回错误时不创建
+type BeforeCreate interface {
+	BeforeCreate(db *MySQl) error
+}
+
+// AfterCreate 创建数据后执行
+type AfterCreate interface {
+	AfterCreate(db *MySQl) error
+}
+
+// BeforeUpdate 修改数据前执行，返回错误时不修改
+type BeforeUpdate interface {
+	BeforeUpdate(db *MySQl) error
+}
+
+// AfterUpdate 修改数据后执行
+type AfterUpdate interface {
+	AfterUpdate(db *MySQl) error
+}
+
+// BeforeDelete 删除数据前执行，返回错误时不删除
+type BeforeDelete interface {
+	BeforeDelete(db *MySQl) error
+}
+
+// AfterDelete 删除数据后执行
+type AfterDelete interface {
+	AfterDelete(db *MySQl) error
+}
+
+// AfterFind 查询数据后执行
+type AfterFind interface {
+	AfterFind(db *MySQl) error
+}
+
+func beforeCreate(db *MySQl, model interface{}) error {
+	if hook, ok := model.(BeforeCreate); ok {
+		return hook.BeforeCreate(db)
+	}
+
+	return nil
+}
+
+func afterCreate(db *MySQl, model interface{}) error {
+	if hook, ok := model.(AfterCreate); ok {
+		return hook.AfterCreate(db)
+	}
+
+	return nil
+}
+
+func beforeUpdate(db *MySQl, model interface{}) error {
+	if hook, ok := model.(BeforeUpdate); ok {
+		return hook.BeforeUpdate(db)
+	}
+
+	return nil
+}
+
+func afterUpdate(db *MySQl, model interface{}) error {
+	if hook, ok := model.(AfterUpdate); ok {
+		return hook.AfterUpdate(db)
+	}
+
+	return nil
+}
+
+func beforeDelete(db *MySQl, model interface{}) error {
+	if hook, ok := model.(BeforeDelete); ok {
+		return hook.BeforeDelete(db)
+	}
+
+	return nil
+}
+
+func afterDelete(db *MySQl, model interface{}) error {
+	if hook, ok := model.(AfterDelete); ok {
+		return hook.AfterDelete(db)
+	}
+
+	return nil
+}
+
+// afterFind 查询数据后执行，data 可以是结构体指针或者 slice 指针
+func afterFind(db *MySQl, data interface{}) error {
+	if hook, ok := data.(AfterFind); ok {
+		return hook.AfterFind(db)
+	}
+
+	value := reflect.ValueOf(data)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Slice {
+		return nil
+	}
+
+	// 元素没有实现 AfterFind 时不需要遍历
+	elemType := value.Elem().Type().Elem()
+	hookType := reflect.TypeOf((*AfterFind)(nil)).Elem()
+	if !elemType.Implements(hookType) && !reflect.PtrTo(elemType).Implements(hookType) {
+		return nil
+	}
+
+	slice := value.Elem()
+	for i, length := 0, slice.Len(); i < length; i++ {
+		item := slice.Index(i)
+		if item.Kind() != reflect.Ptr {
+			item = item.Addr()
+		}
+
+		if item.IsNil() {
+			continue
+		}
+
+		if err := item.Interface().(AfterFind).AfterFind(db); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
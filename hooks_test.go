@@ -0,0 +1,109 @@
+package mysql
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errHookAbort = errors.New("hook abort")
+
+type HookUser struct {
+	UserId   int64  `db:"user_id" json:"user_id"`
+	Username string `db:"username" json:"username"`
+	Password string `db:"password" json:"password"`
+	Status   int    `db:"status" json:"status"`
+
+	abort bool
+	calls []string
+}
+
+func (*HookUser) TableName() string {
+	return "user"
+}
+
+func (*HookUser) PK() string {
+	return "user_id"
+}
+
+func (u *HookUser) call(name string) error {
+	u.calls = append(u.calls, name)
+	if u.abort {
+		return errHookAbort
+	}
+
+	return nil
+}
+
+func (u *HookUser) BeforeCreate(*MySQl) error { return u.call("BeforeCreate") }
+func (u *HookUser) AfterCreate(*MySQl) error  { return u.call("AfterCreate") }
+func (u *HookUser) BeforeUpdate(*MySQl) error { return u.call("BeforeUpdate") }
+func (u *HookUser) AfterUpdate(*MySQl) error  { return u.call("AfterUpdate") }
+func (u *HookUser) BeforeDelete(*MySQl) error { return u.call("BeforeDelete") }
+func (u *HookUser) AfterDelete(*MySQl) error  { return u.call("AfterDelete") }
+func (u *HookUser) AfterFind(*MySQl) error    { return u.call("AfterFind") }
+
+func TestAfterFind(t *testing.T) {
+	user := &HookUser{}
+	assert.NoError(t, afterFind(nil, user))
+	assert.Equal(t, []string{"AfterFind"}, user.calls)
+
+	users := []*HookUser{{}, nil, {}}
+	assert.NoError(t, afterFind(nil, &users))
+	assert.Equal(t, []string{"AfterFind"}, users[0].calls)
+	assert.Equal(t, []string{"AfterFind"}, users[2].calls)
+
+	values := []HookUser{{}, {}}
+	assert.NoError(t, afterFind(nil, &values))
+	assert.Equal(t, []string{"AfterFind"}, values[1].calls)
+
+	values = []HookUser{{abort: true}, {}}
+	assert.Equal(t, errHookAbort, afterFind(nil, &values))
+	assert.Nil(t, values[1].calls)
+
+	// 没有实现 AfterFind 不处理
+	assert.NoError(t, afterFind(nil, &[]*User{{}}))
+	assert.NoError(t, afterFind(nil, &User{}))
+}
+
+func TestMySQl_Hooks(t *testing.T) {
+	mySQL := NewTestMySQL(t, examplePathName, userPathName)
+
+	user := &HookUser{Username: "hook", Password: "hook", Status: 1}
+	assert.NoError(t, mySQL.Create(user))
+	assert.Equal(t, []string{"BeforeCreate", "AfterCreate"}, user.calls)
+
+	user.calls = nil
+	user.Status = 2
+	_, err := mySQL.Update(user)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"BeforeUpdate", "AfterUpdate"}, user.calls)
+
+	found := &HookUser{UserId: user.UserId}
+	assert.NoError(t, mySQL.Find(found))
+	assert.Equal(t, []string{"AfterFind"}, found.calls)
+
+	users := make([]*HookUser, 0)
+	assert.NoError(t, mySQL.Builder(&users).Where("status", 1).All())
+	for _, item := range users {
+		assert.Equal(t, []string{"AfterFind"}, item.calls)
+	}
+
+	user.calls = nil
+	row, err := mySQL.Delete(user)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), row)
+	assert.Equal(t, []string{"BeforeDelete", "AfterDelete"}, user.calls)
+
+	// Before 钩子返回错误时不执行
+	abort := &HookUser{Username: "abort", abort: true}
+	assert.Equal(t, errHookAbort, mySQL.Create(abort))
+	assert.Equal(t, int64(0), abort.UserId)
+
+	abort = &HookUser{UserId: 1, abort: true}
+	row, err = mySQL.Delete(abort)
+	assert.Equal(t, errHookAbort, err)
+	assert.Equal(t, int64(0), row)
+	assert.NoError(t, mySQL.Find(&User{UserId: 1}))
+}
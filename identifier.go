@@ -0,0 +1,84 @@
+package mysql
+
+import (
+	"regexp"
+	"strings"
+)
+
+// identifierRegexp 合法的字段名称或者表名称，支持：name、t.name、t.*、*、`name`、name AS alias、name alias
+var identifierRegexp = regexp.MustCompile(
+	`^(\*|` + identifierPart + `(\.` + identifierPart + `){0,2}(\.\*)?)(\s+((?i)as\s+)?` + identifierPart + `)?$`,
+)
+
+// jsonColumnRegexp JSON 字段路径，支持：meta->$.plan、meta->>$.plan、meta->plan->name、meta->>$.plan AS plan
+var jsonColumnRegexp = regexp.MustCompile(
+	`^(` + identifierPart + `(\.` + identifierPart + `)?)(->>?)(\S+?)(\s+(?i:as\s+)?(` + identifierPart + `))?$`,
+)
+
+// jsonPathRegexp 合法的 JSON 路径，不允许包含引号和空白字符
+var jsonPathRegexp = regexp.MustCompile(`^\$[\p{L}\p{N}_$.\[\]*]*$`)
+
+const identifierPart = "([\\p{L}\\p{N}_$]+|`[^`]+`)"
+
+// IsIdentifier 是否为合法的字段名称或者表名称
+func IsIdentifier(s string) bool {
+	s = strings.TrimSpace(s)
+	if strings.Index(s, "->") != -1 {
+		_, _, _, _, ok := splitJSONColumn(s)
+		return ok
+	}
+
+	return identifierRegexp.MatchString(s)
+}
+
+// splitJSONColumn 解析 JSON 字段，返回字段名称、操作符(-> 或 ->>)、JSON 路径和别名
+func splitJSONColumn(s string) (column, arrow, path, alias string, ok bool) {
+	matches := jsonColumnRegexp.FindStringSubmatch(strings.TrimSpace(s))
+	if matches == nil {
+		return "", "", "", "", false
+	}
+
+	path, ok = jsonPath(matches[6])
+	return matches[1], matches[5], path, matches[9], ok
+}
+
+// jsonPath 转换为 JSON 路径，plan->name 转换为 $.plan.name
+func jsonPath(path string) (string, bool) {
+	path = strings.TrimSpace(path)
+	if !strings.HasPrefix(path, "$") {
+		path = "$." + strings.Replace(path, "->", ".", -1)
+	}
+
+	return path, jsonPathRegexp.MatchString(path)
+}
+
+// parseOrder 解析排序，支持：username、-username、+username、username:desc、username desc
+func parseOrder(s string) (string, string) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", ""
+	}
+
+	switch s[0] {
+	case '-':
+		return strings.TrimSpace(s[1:]), "desc"
+	case '+':
+		return strings.TrimSpace(s[1:]), "asc"
+	}
+
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ':' || r == ' '
+	})
+
+	switch len(fields) {
+	case 1:
+		return fields[0], "asc"
+	case 2:
+		direction := strings.ToLower(fields[1])
+		if direction == "asc" || direction == "desc" {
+			return fields[0], direction
+		}
+	}
+
+	return "", ""
+}
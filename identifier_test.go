@@ -0,0 +1,75 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsIdentifier(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: "username", want: true},
+		{name: "user.username", want: true},
+		{name: "db.user.username", want: true},
+		{name: "user.*", want: true},
+		{name: "*", want: true},
+		{name: "`user`.`username`", want: true},
+		{name: "user as u", want: true},
+		{name: "user AS `u`", want: true},
+		{name: "t1 username", want: true},
+		{name: "用户", want: true},
+		{name: "", want: false},
+		{name: "username; DROP TABLE user", want: false},
+		{name: "username`; DROP TABLE user; `", want: false},
+		{name: "username desc", want: true},
+		{name: "username, password", want: false},
+		{name: "(SELECT 1)", want: false},
+		{name: "user--", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsIdentifier(tt.name))
+		})
+	}
+}
+
+func Test_parseOrder(t *testing.T) {
+	tests := []struct {
+		input     string
+		column    string
+		direction string
+	}{
+		{input: "username", column: "username", direction: "asc"},
+		{input: " -username ", column: "username", direction: "desc"},
+		{input: "+username", column: "username", direction: "asc"},
+		{input: "username:desc", column: "username", direction: "desc"},
+		{input: "username DESC", column: "username", direction: "desc"},
+		{input: "username:drop", column: "", direction: ""},
+		{input: "", column: "", direction: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			column, direction := parseOrder(tt.input)
+			assert.Equal(t, tt.column, column)
+			assert.Equal(t, tt.direction, direction)
+		})
+	}
+}
+
+func Test_jsonPath(t *testing.T) {
+	path, ok := jsonPath("plan->name")
+	assert.Equal(t, "$.plan.name", path)
+	assert.Equal(t, true, ok)
+
+	path, ok = jsonPath("$.tags[*]")
+	assert.Equal(t, "$.tags[*]", path)
+	assert.Equal(t, true, ok)
+
+	_, ok = jsonPath("$.a') OR ('1")
+	assert.Equal(t, false, ok)
+}
@@ -0,0 +1,34 @@
+package mysql
+
+// JoinClause JOIN 的 ON 条件，例如：
+//
+//	Join("order as o", func(join *JoinClause) {
+//	    join.On("o.user_id", "=", "user.user_id").Where("o.status", 1)
+//	})
+type JoinClause struct {
+	builder *Builder
+}
+
+// On 关联字段比较
+func (j *JoinClause) On(first, operator, second string) *JoinClause {
+	j.builder.whereColumn("AND", first, operator, second)
+	return j
+}
+
+// OrOn 或者关联字段比较
+func (j *JoinClause) OrOn(first, operator, second string) *JoinClause {
+	j.builder.whereColumn("OR", first, operator, second)
+	return j
+}
+
+// Where 关联条件，参数和 Builder.Where 一致
+func (j *JoinClause) Where(column interface{}, args ...interface{}) *JoinClause {
+	j.builder.toWhere("AND", column, args...)
+	return j
+}
+
+// OrWhere 或者关联条件，参数和 Builder.OrWhere 一致
+func (j *JoinClause) OrWhere(column interface{}, args ...interface{}) *JoinClause {
+	j.builder.toWhere("OR", column, args...)
+	return j
+}
@@ -0,0 +1,57 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder_JoinClause(t *testing.T) {
+	query, bindings, err := NewBuilder(&MySQl{}, &User{}).
+		Select("user.*").
+		Join("user as u", func(join *JoinClause) {
+			join.On("u.user_id", "=", "user.user_id").
+				OrOn("u.username", "=", "user.username").
+				Where("u.status", 1).
+				OrWhere("u.created_at", ">", "2020-11-01")
+		}).
+		LeftJoin("user as l", func(join *JoinClause) {}).
+		Where("user.status", 2).
+		ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT `user`.* FROM `user` JOIN `user` AS `u` ON (`u`.`user_id` = `user`.`user_id` OR `u`.`username` = `user`.`username` AND `u`.`status` = ? OR `u`.`created_at` > ?) LEFT JOIN `user` AS `l` WHERE `user`.`status` = ?", query)
+	assert.Equal(t, []interface{}{1, "2020-11-01", 2}, bindings)
+}
+
+func TestBuilder_CrossJoin(t *testing.T) {
+	s := NewBuilder(&MySQl{}, &User{}).CrossJoin("user as u").String()
+	assert.Equal(t, "SELECT * FROM `user` CROSS JOIN `user` AS `u`", s)
+}
+
+func TestBuilder_JoinSub(t *testing.T) {
+	sub := NewBuilder(&MySQl{}, &User{}).Select("status").Where("status", ">", 0).GroupBy("status")
+	query, bindings, err := NewBuilder(&MySQl{}, &User{}).
+		JoinSub(sub, "s", func(join *JoinClause) {
+			join.On("s.status", "=", "user.status")
+		}).
+		LeftJoinSub(sub, "l", "l.status = user.status AND l.status != ?", 3).
+		RightJoinSub(sub, "r", "r.status = user.status").
+		Where("user.user_id", 1).
+		ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `user` JOIN (SELECT `status` FROM `user` WHERE `status` > ? GROUP BY `status`) AS `s` ON (`s`.`status` = `user`.`status`) LEFT JOIN (SELECT `status` FROM `user` WHERE `status` > ? GROUP BY `status`) AS `l` ON (l.status = user.status AND l.status != ?) RIGHT JOIN (SELECT `status` FROM `user` WHERE `status` > ? GROUP BY `status`) AS `r` ON (r.status = user.status) WHERE `user`.`user_id` = ?", query)
+	assert.Equal(t, []interface{}{0, 0, 3, 0, 1}, bindings)
+}
+
+func TestBuilder_JoinErr(t *testing.T) {
+	b := NewBuilder(&MySQl{}, &User{}).Join("user as u", 1)
+	assert.EqualError(t, b.Err(), "join on must be a string or func(*JoinClause), got int")
+
+	b = NewBuilder(&MySQl{}, &User{}).Join("user as u", func(join *JoinClause) {
+		join.On("u.user_id", "==", "user.user_id")
+	})
+	assert.Error(t, b.Err())
+
+	b = NewBuilder(&MySQl{}, &User{}).JoinSub(NewBuilder(&MySQl{}, &User{}).Where(1), "s; --", "1 = 1")
+	assert.Error(t, b.Err())
+}
@@ -0,0 +1,176 @@
+package mysql
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// WhereJsonContains JSON 字段包含 value，column 可以指定路径，例如：WhereJsonContains("meta->$.tags", "vip")
+func (b *Builder) WhereJsonContains(column string, value interface{}) *Builder {
+	return b.whereJsonContains("AND", column, value, false)
+}
+
+// OrWhereJsonContains 或者 JSON 字段包含 value
+func (b *Builder) OrWhereJsonContains(column string, value interface{}) *Builder {
+	return b.whereJsonContains("OR", column, value, false)
+}
+
+// WhereJsonDoesntContain JSON 字段不包含 value
+func (b *Builder) WhereJsonDoesntContain(column string, value interface{}) *Builder {
+	return b.whereJsonContains("AND", column, value, true)
+}
+
+// OrWhereJsonDoesntContain 或者 JSON 字段不包含 value
+func (b *Builder) OrWhereJsonDoesntContain(column string, value interface{}) *Builder {
+	return b.whereJsonContains("OR", column, value, true)
+}
+
+// WhereJsonLength JSON 字段长度比较，例如：WhereJsonLength("meta->$.tags", ">", 1)
+func (b *Builder) WhereJsonLength(column, operator string, length int) *Builder {
+	return b.whereJsonLength("AND", column, operator, length)
+}
+
+// OrWhereJsonLength 或者 JSON 字段长度比较
+func (b *Builder) OrWhereJsonLength(column, operator string, length int) *Builder {
+	return b.whereJsonLength("OR", column, operator, length)
+}
+
+// JsonSet 修改 JSON 字段的部分数据，用于 UpdateMap，values 的 key 为 JSON 路径，例如：
+//
+//	UpdateMap(map[string]interface{}{"meta": JsonSet("meta", map[string]interface{}{"$.plan": "pro"})})
+func JsonSet(column string, values map[string]interface{}) Expression {
+	if !IsIdentifier(column) {
+		return Expression{err: fmt.Errorf("invalid identifier %q", column)}
+	}
+
+	paths := make([]string, 0, len(values))
+	for path := range values {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+	items := make([]string, 0, len(paths))
+	args := make([]interface{}, 0, len(paths))
+	for _, key := range paths {
+		path, ok := jsonPath(key)
+		if !ok {
+			return Expression{err: fmt.Errorf("invalid json path %q", key)}
+		}
+
+		value, placeholder, err := jsonValue(values[key])
+		if err != nil {
+			return Expression{err: err}
+		}
+
+		items = append(items, fmt.Sprintf("'%s', %s", path, placeholder))
+		args = append(args, value)
+	}
+
+	if len(items) == 0 {
+		return Expression{err: fmt.Errorf("json set %s needs at least one path", column)}
+	}
+
+	// 字段为 NULL 时 JSON_SET 返回 NULL，使用空对象代替
+	return Raw(fmt.Sprintf("JSON_SET(COALESCE(%s, JSON_OBJECT()), %s)", (&Builder{}).warp(column), strings.Join(items, ", ")), args...)
+}
+
+// JsonRemove 删除 JSON 字段中指定路径的数据，用于 UpdateMap
+func JsonRemove(column string, paths ...string) Expression {
+	if !IsIdentifier(column) {
+		return Expression{err: fmt.Errorf("invalid identifier %q", column)}
+	}
+
+	if len(paths) == 0 {
+		return Expression{err: fmt.Errorf("json remove %s needs at least one path", column)}
+	}
+
+	items := make([]string, 0, len(paths))
+	for _, key := range paths {
+		path, ok := jsonPath(key)
+		if !ok {
+			return Expression{err: fmt.Errorf("invalid json path %q", key)}
+		}
+
+		items = append(items, fmt.Sprintf("'%s'", path))
+	}
+
+	return Raw(fmt.Sprintf("JSON_REMOVE(%s, %s)", (&Builder{}).warp(column), strings.Join(items, ", ")))
+}
+
+func (b *Builder) whereJsonContains(boolean, column string, value interface{}, not bool) *Builder {
+	target, path, ok := b.jsonTarget(column)
+	if !ok {
+		return b
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return b.addError(fmt.Errorf("where json contains %s: %v", column, err))
+	}
+
+	if not {
+		boolean += " NOT"
+	}
+
+	if path != "" {
+		path = fmt.Sprintf(", '%s'", path)
+	}
+
+	b.wheres = append(b.wheres, fmt.Sprintf("%s JSON_CONTAINS(%s, ?%s)", boolean, target, path))
+	b.bindings = append(b.bindings, string(data))
+	return b
+}
+
+func (b *Builder) whereJsonLength(boolean, column, operator string, length int) *Builder {
+	if !columnOperators[operator] {
+		return b.addError(fmt.Errorf("where json length %s has unsupported operator %q", column, operator))
+	}
+
+	target, path, ok := b.jsonTarget(column)
+	if !ok {
+		return b
+	}
+
+	if path != "" {
+		target += fmt.Sprintf(", '%s'", path)
+	}
+
+	b.wheres = append(b.wheres, fmt.Sprintf("%s JSON_LENGTH(%s) %s ?", boolean, target, operator))
+	b.bindings = append(b.bindings, length)
+	return b
+}
+
+// jsonTarget JSON 函数的字段和路径参数，meta->$.tags 返回 `meta` 和 $.tags
+func (b *Builder) jsonTarget(column string) (string, string, bool) {
+	if !b.identifier(column) {
+		return "", "", false
+	}
+
+	if name, _, path, _, ok := splitJSONColumn(column); ok {
+		return b.warp(name), path, true
+	}
+
+	return b.warp(column), "", true
+}
+
+// jsonValue JSON_SET 的值，map、slice 和 struct 转换为 JSON
+func jsonValue(value interface{}) (interface{}, string, error) {
+	switch reflect.Indirect(reflect.ValueOf(value)).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct:
+		if _, ok := value.([]byte); ok {
+			return value, "?", nil
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, "", err
+		}
+
+		return string(data), "CAST(? AS JSON)", nil
+	}
+
+	return value, "?", nil
+}
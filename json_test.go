@@ -0,0 +1,81 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder_WhereJsonPath(t *testing.T) {
+	b := NewBuilder(&MySQl{}, &User{}).
+		Select("user_id", "meta->>$.plan as plan").
+		Where("meta->>$.plan", "pro").
+		Where("meta->limits->users", ">", 10).
+		OrderBy("meta->>$.plan", "asc")
+	assert.NoError(t, b.Err())
+	assert.Equal(t, "SELECT `user_id`, `meta`->>'$.plan' AS `plan` FROM `user` WHERE `meta`->>'$.plan' = ? AND `meta`->'$.limits.users' > ? ORDER BY `meta`->>'$.plan' ASC", b.String())
+
+	b = NewBuilder(&MySQl{}, &User{}).Where("meta->>$.plan') = 'x' OR ('1", 1)
+	assert.Error(t, b.Err())
+}
+
+func TestBuilder_WhereJsonContains(t *testing.T) {
+	b := NewBuilder(&MySQl{}, &User{}).
+		WhereJsonContains("meta->$.tags", "vip").
+		OrWhereJsonContains("roles", []int{1, 2}).
+		WhereJsonDoesntContain("meta->tags", "test").
+		OrWhereJsonDoesntContain("roles", 3)
+	assert.NoError(t, b.Err())
+	assert.Equal(t, "SELECT * FROM `user` WHERE JSON_CONTAINS(`meta`, ?, '$.tags') OR JSON_CONTAINS(`roles`, ?) AND NOT JSON_CONTAINS(`meta`, ?, '$.tags') OR NOT JSON_CONTAINS(`roles`, ?)", b.String())
+	assert.Equal(t, []interface{}{`"vip"`, `[1,2]`, `"test"`, `3`}, b.bindings)
+}
+
+func TestBuilder_WhereJsonLength(t *testing.T) {
+	b := NewBuilder(&MySQl{}, &User{}).
+		WhereJsonLength("meta->$.tags", ">", 1).
+		OrWhereJsonLength("roles", "=", 0)
+	assert.NoError(t, b.Err())
+	assert.Equal(t, "SELECT * FROM `user` WHERE JSON_LENGTH(`meta`, '$.tags') > ? OR JSON_LENGTH(`roles`) = ?", b.String())
+	assert.Equal(t, []interface{}{1, 0}, b.bindings)
+
+	b = NewBuilder(&MySQl{}, &User{}).WhereJsonLength("roles", "in", 1)
+	assert.Error(t, b.Err())
+}
+
+func TestJsonSet(t *testing.T) {
+	e := JsonSet("meta", map[string]interface{}{
+		"$.plan":  "pro",
+		"limits":  map[string]int{"users": 10},
+		"$.tags":  []string{"vip"},
+		"enabled": true,
+	})
+	assert.NoError(t, e.Err())
+	assert.Equal(t, "JSON_SET(COALESCE(`meta`, JSON_OBJECT()), '$.plan', ?, '$.tags', CAST(? AS JSON), '$.enabled', ?, '$.limits', CAST(? AS JSON))", e.SQL)
+	assert.Equal(t, []interface{}{"pro", `["vip"]`, true, `{"users":10}`}, e.Args)
+
+	assert.EqualError(t, JsonSet("meta", map[string]interface{}{"$.a'": 1}).Err(), `invalid json path "$.a'"`)
+	assert.Error(t, JsonSet("meta;", map[string]interface{}{"$.a": 1}).Err())
+	assert.Error(t, JsonSet("meta", nil).Err())
+}
+
+func TestJsonRemove(t *testing.T) {
+	e := JsonRemove("meta", "$.plan", "tags[0]")
+	assert.NoError(t, e.Err())
+	assert.Equal(t, "JSON_REMOVE(`meta`, '$.plan', '$.tags[0]')", e.SQL)
+	assert.Equal(t, 0, len(e.Args))
+
+	assert.Error(t, JsonRemove("meta").Err())
+	assert.Error(t, JsonRemove("meta", "$.a' OR 1").Err())
+}
+
+func TestBuilder_UpdateMapJson(t *testing.T) {
+	query, bindings, err := NewBuilder(&MySQl{}, &User{}).Where("user_id", 1).ToUpdateMapSQL(map[string]interface{}{
+		"meta": JsonSet("meta", map[string]interface{}{"$.plan": "pro"}),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE `user` SET `meta` = JSON_SET(COALESCE(`meta`, JSON_OBJECT()), '$.plan', ?) WHERE `user_id` = ?", query)
+	assert.Equal(t, []interface{}{"pro", 1}, bindings)
+
+	_, _, err = NewBuilder(&MySQl{}, &User{}).ToUpdateMapSQL(map[string]interface{}{"meta": JsonRemove("meta")})
+	assert.EqualError(t, err, "json remove meta needs at least one path")
+}
@@ -0,0 +1,191 @@
+package mysql
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// 主键生成策略
+const (
+	KeyUUIDv4    = "uuid_v4"
+	KeyUUIDv7    = "uuid_v7"
+	KeySnowflake = "snowflake"
+)
+
+// GeneratedKey 主键生成策略，新增数据时主键为零值会先生成主键，KeyStrategy 返回 KeyUUIDv4、KeyUUIDv7 或者 KeySnowflake
+// UUID 主键字段可以是 string、[]byte 或者 [16]byte，雪花 ID 主键字段可以是整数或者 string
+type GeneratedKey interface {
+	KeyStrategy() string
+}
+
+// DefaultSnowflake 默认的雪花 ID 生成器，多个服务部署时需要设置不同的节点
+var DefaultSnowflake = NewSnowflake(0)
+
+// snowflakeEpoch 雪花 ID 的起始时间 2020-01-01 00:00:00 UTC，单位毫秒
+const snowflakeEpoch int64 = 1577836800000
+
+const (
+	snowflakeNodeBits     = 10
+	snowflakeSequenceBits = 12
+	snowflakeMaxNode      = 1<<snowflakeNodeBits - 1
+	snowflakeMaxSequence  = 1<<snowflakeSequenceBits - 1
+)
+
+// Snowflake 雪花 ID 生成器，41 位毫秒时间 + 10 位节点 + 12 位序号
+type Snowflake struct {
+	mu       sync.Mutex
+	node     int64
+	last     int64
+	sequence int64
+}
+
+// NewSnowflake 创建雪花 ID 生成器，node 取值 0-1023
+func NewSnowflake(node int64) *Snowflake {
+	return &Snowflake{node: node & snowflakeMaxNode}
+}
+
+// Next 生成下一个 ID，同一毫秒内序号用完时等待下一毫秒
+func (s *Snowflake) Next() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	if now < s.last {
+		// 时钟回拨时继续使用上次的时间
+		now = s.last
+	}
+
+	if now == s.last {
+		s.sequence = (s.sequence + 1) & snowflakeMaxSequence
+		if s.sequence == 0 {
+			for now <= s.last {
+				now = time.Now().UnixNano() / int64(time.Millisecond)
+			}
+		}
+	} else {
+		s.sequence = 0
+	}
+
+	s.last = now
+	return (now-snowflakeEpoch)<<(snowflakeNodeBits+snowflakeSequenceBits) |
+		s.node<<snowflakeSequenceBits |
+		s.sequence
+}
+
+// NewUUIDv4 生成随机的 UUID v4
+func NewUUIDv4() string {
+	return formatUUID(uuidV4())
+}
+
+// NewUUIDv7 生成按时间排序的 UUID v7，适合作为 InnoDB 主键
+func NewUUIDv7() string {
+	return formatUUID(uuidV7())
+}
+
+func uuidV4() [16]byte {
+	var uuid [16]byte
+	if _, err := rand.Read(uuid[:]); err != nil {
+		panic(err)
+	}
+
+	uuid[6] = uuid[6]&0x0f | 0x40
+	uuid[8] = uuid[8]&0x3f | 0x80
+	return uuid
+}
+
+func uuidV7() [16]byte {
+	uuid := uuidV4()
+	ms := uint64(time.Now().UnixNano() / int64(time.Millisecond))
+	for i := 0; i < 6; i++ {
+		uuid[i] = byte(ms >> (40 - 8*i))
+	}
+
+	uuid[6] = uuid[6]&0x0f | 0x70
+	return uuid
+}
+
+func formatUUID(uuid [16]byte) string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], uuid[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], uuid[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], uuid[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], uuid[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], uuid[10:])
+	return string(buf[:])
+}
+
+// generateKey 模型实现了 GeneratedKey 并且主键为零值时生成主键
+func generateKey(model Model) error {
+	generated, ok := model.(GeneratedKey)
+	if !ok {
+		return nil
+	}
+
+	field := structField(reflect.ValueOf(model).Elem(), model.PK())
+	if !field.IsValid() || !field.CanSet() {
+		return fmt.Errorf("primary key %s has no field on %T", model.PK(), model)
+	}
+
+	if !field.IsZero() {
+		return nil
+	}
+
+	strategy := generated.KeyStrategy()
+	switch strategy {
+	case KeyUUIDv4:
+		return setUUIDKey(field, uuidV4())
+	case KeyUUIDv7:
+		return setUUIDKey(field, uuidV7())
+	case KeySnowflake:
+		return setSnowflakeKey(field, DefaultSnowflake.Next())
+	}
+
+	return fmt.Errorf("unsupported key strategy %q", strategy)
+}
+
+func setUUIDKey(field reflect.Value, uuid [16]byte) error {
+	switch {
+	case field.Kind() == reflect.String:
+		field.SetString(formatUUID(uuid))
+	case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.Uint8:
+		field.SetBytes(uuid[:])
+	case field.Kind() == reflect.Array && field.Len() == 16 && field.Type().Elem().Kind() == reflect.Uint8:
+		field.Set(reflect.ValueOf(uuid).Convert(field.Type()))
+	default:
+		return fmt.Errorf("uuid key does not support %s field", field.Type())
+	}
+
+	return nil
+}
+
+func setSnowflakeKey(field reflect.Value, id int64) error {
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if field.OverflowInt(id) {
+			return fmt.Errorf("snowflake key %d overflows %s field", id, field.Type())
+		}
+
+		field.SetInt(id)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if field.OverflowUint(uint64(id)) {
+			return fmt.Errorf("snowflake key %d overflows %s field", id, field.Type())
+		}
+
+		field.SetUint(uint64(id))
+	case reflect.String:
+		field.SetString(strconv.FormatInt(id, 10))
+	default:
+		return fmt.Errorf("snowflake key does not support %s field", field.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,177 @@
+package mysql
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Token struct {
+	TokenId string `db:"token_id" json:"token_id"`
+	Name    string `db:"name" json:"name"`
+}
+
+func (*Token) TableName() string {
+	return "token"
+}
+
+func (*Token) PK() string {
+	return "token_id"
+}
+
+func (*Token) KeyStrategy() string {
+	return KeyUUIDv7
+}
+
+type TenantUser struct {
+	TenantId uint64 `db:"tenant_id" json:"tenant_id"`
+	UserId   uint64 `db:"user_id" json:"user_id"`
+	Name     string `db:"name" json:"name"`
+}
+
+func (*TenantUser) TableName() string {
+	return "tenant_user"
+}
+
+func (*TenantUser) PK() string {
+	return "user_id"
+}
+
+func (*TenantUser) PKs() []string {
+	return []string{"tenant_id", "user_id"}
+}
+
+func (*TenantUser) KeyStrategy() string {
+	return KeySnowflake
+}
+
+type BinaryToken struct {
+	TokenId [16]byte `db:"token_id"`
+}
+
+func (*BinaryToken) TableName() string {
+	return "token"
+}
+
+func (*BinaryToken) PK() string {
+	return "token_id"
+}
+
+func (*BinaryToken) KeyStrategy() string {
+	return KeyUUIDv4
+}
+
+type UnknownKey struct {
+	Id int64 `db:"id"`
+}
+
+func (*UnknownKey) TableName() string {
+	return "unknown"
+}
+
+func (*UnknownKey) PK() string {
+	return "id"
+}
+
+func (*UnknownKey) KeyStrategy() string {
+	return "unknown"
+}
+
+func TestNewUUID(t *testing.T) {
+	v4 := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	v7 := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	assert.Regexp(t, v4, NewUUIDv4())
+	assert.Regexp(t, v7, NewUUIDv7())
+	assert.NotEqual(t, NewUUIDv4(), NewUUIDv4())
+
+	// v7 的时间部分递增
+	first := NewUUIDv7()
+	assert.True(t, first[:8] <= NewUUIDv7()[:8])
+}
+
+func TestSnowflake_Next(t *testing.T) {
+	snowflake := NewSnowflake(1)
+	exists := make(map[int64]bool)
+	last := int64(0)
+	for i := 0; i < 10000; i++ {
+		id := snowflake.Next()
+		assert.True(t, id > last)
+		assert.False(t, exists[id])
+		assert.Equal(t, int64(1), id>>snowflakeSequenceBits&snowflakeMaxNode)
+		exists[id], last = true, id
+	}
+}
+
+func TestGenerateKey(t *testing.T) {
+	token := &Token{}
+	assert.NoError(t, generateKey(token))
+	assert.Equal(t, 36, len(token.TokenId))
+
+	// 已经有主键不生成
+	token = &Token{TokenId: "exists"}
+	assert.NoError(t, generateKey(token))
+	assert.Equal(t, "exists", token.TokenId)
+
+	tenantUser := &TenantUser{TenantId: 1}
+	assert.NoError(t, generateKey(tenantUser))
+	assert.NotEqual(t, uint64(0), tenantUser.UserId)
+
+	binary := &BinaryToken{}
+	assert.NoError(t, generateKey(binary))
+	assert.NotEqual(t, [16]byte{}, binary.TokenId)
+
+	assert.Error(t, generateKey(&UnknownKey{}))
+	assert.NoError(t, generateKey(&User{}))
+}
+
+func Test_setSnowflakeKey(t *testing.T) {
+	var key struct {
+		Int    int
+		Uint   uint
+		Int32  int32
+		String string
+	}
+
+	value := reflect.ValueOf(&key).Elem()
+	assert.NoError(t, setSnowflakeKey(value.Field(0), 1<<40))
+	assert.Equal(t, int64(1<<40), int64(key.Int))
+	assert.NoError(t, setSnowflakeKey(value.Field(1), 1<<40))
+	assert.Equal(t, uint64(1<<40), uint64(key.Uint))
+	assert.NoError(t, setSnowflakeKey(value.Field(3), 1<<40))
+	assert.Equal(t, "1099511627776", key.String)
+
+	// 字段长度不够时返回错误
+	assert.EqualError(t, setSnowflakeKey(value.Field(2), 1<<40), "snowflake key 1099511627776 overflows int32 field")
+	assert.Equal(t, int32(0), key.Int32)
+}
+
+func TestMySQl_CreateGeneratedKey(t *testing.T) {
+	mySQL := NewTestMySQL(t, examplePathName)
+
+	token := &Token{Name: "token"}
+	assert.NoError(t, mySQL.Create(token))
+	assert.Equal(t, 36, len(token.TokenId))
+	assert.NoError(t, mySQL.Find(&Token{TokenId: token.TokenId}))
+
+	tenantUser := &TenantUser{TenantId: 1, Name: "name"}
+	assert.NoError(t, mySQL.Create(tenantUser))
+	assert.NotEqual(t, uint64(0), tenantUser.UserId)
+	assert.NoError(t, mySQL.Create(&TenantUser{TenantId: 2, UserId: tenantUser.UserId, Name: "other"}))
+
+	// 复合主键修改、删除
+	tenantUser.Name = "update"
+	row, err := mySQL.Update(tenantUser)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), row)
+
+	other := &TenantUser{TenantId: 2, UserId: tenantUser.UserId}
+	assert.NoError(t, mySQL.Find(other))
+	assert.Equal(t, "other", other.Name)
+
+	row, err = mySQL.Delete(&TenantUser{TenantId: 1, UserId: tenantUser.UserId})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), row)
+	assert.NoError(t, mySQL.Find(other))
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 )
 
@@ -17,6 +18,11 @@ type Model interface {
 	PK() string
 }
 
+// PrimaryKeys 复合主键，PKs 返回全部主键字段名称，PK 返回自增字段名称
+type PrimaryKeys interface {
+	PKs() []string
+}
+
 type CreatedAtName interface {
 	CreatedAtName() string
 }
@@ -25,24 +31,106 @@ type UpdatedAtName interface {
 	UpdatedAtName() string
 }
 
+// SoftDeletes 软删除，DeletedAtName 返回软删除字段名称
+type SoftDeletes interface {
+	DeletedAtName() string
+}
+
+// Versioned 乐观锁，VersionName 返回版本号字段名称，字段需要是整数类型
+type Versioned interface {
+	VersionName() string
+}
+
 type AutoTimestamps interface {
 	AutoTimestamps() bool
 }
 
+// AutoRefresh 新增、修改数据后是否重新查询数据，用于获取数据库默认值、生成列和触发器修改的值
+type AutoRefresh interface {
+	AutoRefresh() bool
+}
+
 type TimestampsValue interface {
 	TimestampsValue() interface{}
 }
 
+// GetPKValue 获取主键的值，字段不存在时返回 nil
 func GetPKValue(model Model) interface{} {
-	return reflect.ValueOf(model).Elem().FieldByName(Studly(model.PK())).Interface()
+	return fieldValue(reflect.ValueOf(model).Elem(), model.PK())
 }
 
-func SetPKValue(model Model, id int64) {
-	idField := Studly(model.PK())
-	value := reflect.ValueOf(model).Elem().FieldByName(idField)
-	if value.IsZero() && value.CanSet() {
+// SetPKValue 设置自增主键的值，只有整数类型并且为零值的主键才会设置
+func SetPKValue(model Model, id int64) bool {
+	value := structField(reflect.ValueOf(model).Elem(), model.PK())
+	if id <= 0 || !value.IsValid() || !value.IsZero() || !value.CanSet() {
+		return false
+	}
+
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		value.SetInt(id)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value.SetUint(uint64(id))
+	default:
+		return false
+	}
+
+	return true
+}
+
+// GetPKColumnNames 获取主键字段名称，实现了 PrimaryKeys 时返回复合主键
+func GetPKColumnNames(model Model) []string {
+	if primaryKeys, ok := model.(PrimaryKeys); ok {
+		if pks := primaryKeys.PKs(); len(pks) > 0 {
+			return pks
+		}
+	}
+
+	return []string{model.PK()}
+}
+
+// GetPKValues 获取主键的值，顺序和 GetPKColumnNames 一致，字段不存在时为 nil
+func GetPKValues(model Model) []interface{} {
+	value := reflect.ValueOf(model).Elem()
+	pks := GetPKColumnNames(model)
+	values := make([]interface{}, 0, len(pks))
+	for _, pk := range pks {
+		values = append(values, fieldValue(value, pk))
+	}
+
+	return values
+}
+
+// fieldValue 获取字段名称对应的结构体字段的值，字段不存在时返回 nil
+func fieldValue(value reflect.Value, column string) interface{} {
+	field := structField(value, column)
+	if !field.IsValid() {
+		return nil
+	}
+
+	return field.Interface()
+}
+
+// hasZeroPK 是否有主键为零值
+func hasZeroPK(model Model) bool {
+	for _, value := range GetPKValues(model) {
+		if value == nil || reflect.ValueOf(value).IsZero() {
+			return true
+		}
 	}
+
+	return false
+}
+
+// pkWhere 使用主键查询的条件，例如：`tenant_id` = ? AND `id` = ?
+func pkWhere(model Model) (string, []interface{}) {
+	pks := GetPKColumnNames(model)
+	where := make([]string, 0, len(pks))
+	for _, pk := range pks {
+		where = append(where, fmt.Sprintf("`%s` = ?", pk))
+	}
+
+	return strings.Join(where, " AND "), GetPKValues(model)
 }
 
 func SetCreateAutoTimestamps(model Model) bool {
@@ -75,12 +163,11 @@ func SetUpdateAutoTimestamps(model Model) bool {
 
 // SetStructNameValue 设置结构体指定字段的值
 func SetStructNameValue(value reflect.Value, column string, structNameValue interface{}) bool {
-	structName := Studly(column)
-	if _, ok := value.Type().FieldByName(structName); !ok {
+	valueOf := structField(value, column)
+	if !valueOf.IsValid() || structNameValue == nil || !reflect.TypeOf(structNameValue).AssignableTo(valueOf.Type()) {
 		return false
 	}
 
-	valueOf := value.FieldByName(structName)
 	if valueOf.IsZero() && valueOf.CanSet() {
 		valueOf.Set(reflect.ValueOf(structNameValue))
 		return true
@@ -109,6 +196,15 @@ func IsAutoTimestamps(model Model) bool {
 	return true
 }
 
+// IsAutoRefresh 新增、修改数据后是否需要重新查询数据
+func IsAutoRefresh(model Model) bool {
+	if refresh, ok := model.(AutoRefresh); ok {
+		return refresh.AutoRefresh()
+	}
+
+	return false
+}
+
 // GetCreatedAtColumnName 获取创建时间字段名称
 func GetCreatedAtColumnName(model Model) string {
 	if createdAtColumn, ok := model.(CreatedAtName); ok {
@@ -127,6 +223,36 @@ func GetUpdatedAtColumnName(model Model) string {
 	return UpdatedAt
 }
 
+// GetDeletedAtColumnName 获取软删除字段名称，没有实现 SoftDeletes 时返回空字符串
+func GetDeletedAtColumnName(model Model) string {
+	if softDeletes, ok := model.(SoftDeletes); ok {
+		return softDeletes.DeletedAtName()
+	}
+
+	return ""
+}
+
+// GetVersionColumnName 获取乐观锁版本号字段名称，没有实现 Versioned 时返回空字符串
+func GetVersionColumnName(model Model) string {
+	if versioned, ok := model.(Versioned); ok {
+		return versioned.VersionName()
+	}
+
+	return ""
+}
+
+// getVersionValue 获取乐观锁版本号字段，字段不存在或者不是整数类型时返回错误
+func getVersionValue(model Model, column string) (reflect.Value, error) {
+	value := structField(reflect.ValueOf(model).Elem(), column)
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return value, nil
+	}
+
+	return value, fmt.Errorf("version column %s must be an integer field of %T", column, model)
+}
+
 func GetModel(model interface{}) (Model, error) {
 	if m, ok := model.(Model); ok {
 		return m, nil
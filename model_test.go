@@ -91,6 +91,70 @@ func (*LoginUser) PK() string {
 	return "user_id"
 }
 
+type SoftUser struct {
+	UserId    int64  `db:"user_id" json:"user_id"`
+	Username  string `db:"username" json:"username"`
+	Password  string `db:"password" json:"password"`
+	Status    int    `db:"status" json:"status"`
+	CreatedAt Time   `db:"created_at" json:"created_at"`
+	UpdatedAt Time   `db:"updated_at" json:"updated_at"`
+	DeletedAt Time   `db:"deleted_at" json:"deleted_at"`
+}
+
+func (*SoftUser) TableName() string {
+	return "user"
+}
+
+func (*SoftUser) PK() string {
+	return "user_id"
+}
+
+func (*SoftUser) DeletedAtName() string {
+	return "deleted_at"
+}
+
+func (*SoftUser) TimestampsValue() interface{} {
+	return Time(time.Now())
+}
+
+type VersionUser struct {
+	UserId   int64  `db:"user_id" json:"user_id"`
+	Username string `db:"username" json:"username"`
+	Status   int    `db:"status" json:"status"`
+	Version  int    `db:"version" json:"version"`
+}
+
+func (*VersionUser) TableName() string {
+	return "user"
+}
+
+func (*VersionUser) PK() string {
+	return "user_id"
+}
+
+func (*VersionUser) VersionName() string {
+	return "version"
+}
+
+func (*VersionUser) AutoTimestamps() bool {
+	return false
+}
+
+func TestGetVersionColumnName(t *testing.T) {
+	assert.Equal(t, "version", GetVersionColumnName(&VersionUser{}))
+	assert.Equal(t, "", GetVersionColumnName(&User{}))
+
+	value, err := getVersionValue(&VersionUser{Version: 2}, "version")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), value.Int())
+
+	_, err = getVersionValue(&VersionUser{}, "username")
+	assert.Error(t, err)
+
+	_, err = getVersionValue(&VersionUser{}, "not_exists")
+	assert.Error(t, err)
+}
+
 func TestGetPkValue(t *testing.T) {
 	fmt.Printf("%T", GetPKValue(&User{UserId: 1}))
 	assert.Equal(t, int64(0), GetPKValue(&User{}))
@@ -105,6 +169,41 @@ func TestSetPKValue(t *testing.T) {
 	assert.Equal(t, int64(2), user.UserId)
 }
 
+func TestSetPKValue_Types(t *testing.T) {
+	tenantUser := &TenantUser{}
+	assert.True(t, SetPKValue(tenantUser, 3))
+	assert.Equal(t, uint64(3), tenantUser.UserId)
+
+	// 字符串主键不使用自增ID
+	token := &Token{}
+	assert.False(t, SetPKValue(token, 3))
+	assert.Equal(t, "", token.TokenId)
+
+	assert.False(t, SetPKValue(&User{}, 0))
+}
+
+func TestIsAutoRefresh(t *testing.T) {
+	assert.True(t, IsAutoRefresh(&RefreshUser{}))
+	assert.False(t, IsAutoRefresh(&User{}))
+}
+
+func TestHasZeroPK(t *testing.T) {
+	assert.True(t, hasZeroPK(&User{}))
+	assert.False(t, hasZeroPK(&User{UserId: 1}))
+	assert.True(t, hasZeroPK(&TenantUser{TenantId: 1}))
+	assert.False(t, hasZeroPK(&TenantUser{TenantId: 1, UserId: 2}))
+}
+
+func TestGetPKColumnNames(t *testing.T) {
+	assert.Equal(t, []string{"user_id"}, GetPKColumnNames(&User{}))
+	assert.Equal(t, []string{"tenant_id", "user_id"}, GetPKColumnNames(&TenantUser{}))
+	assert.Equal(t, []interface{}{uint64(1), uint64(2)}, GetPKValues(&TenantUser{TenantId: 1, UserId: 2}))
+
+	where, args := pkWhere(&TenantUser{TenantId: 1, UserId: 2})
+	assert.Equal(t, "`tenant_id` = ? AND `user_id` = ?", where)
+	assert.Equal(t, []interface{}{uint64(1), uint64(2)}, args)
+}
+
 func TestSetCreateAutoTimestamps(t *testing.T) {
 	timestamps := &UserTimestamps{}
 	auto := SetCreateAutoTimestamps(timestamps)
@@ -178,6 +277,13 @@ func TestSetTimestampsValue(t *testing.T) {
 	has = SetStructNameValue(value, "user_id", int64(1))
 	assert.Equal(t, true, has)
 	assert.Equal(t, int64(1), user.UserId)
+
+	// 类型不一致
+	has = SetStructNameValue(value, "status", "1")
+	assert.Equal(t, false, has)
+
+	has = SetStructNameValue(value, "status", nil)
+	assert.Equal(t, false, has)
 }
 
 func TestGetModel(t *testing.T) {
@@ -215,3 +321,8 @@ func TestGetModel(t *testing.T) {
 	_, err = GetModel(&d)
 	assert.NoError(t, err)
 }
+
+func TestGetDeletedAtColumnName(t *testing.T) {
+	assert.Equal(t, "", GetDeletedAtColumnName(&User{}))
+	assert.Equal(t, "deleted_at", GetDeletedAtColumnName(&SoftUser{}))
+}
@@ -2,7 +2,9 @@ package mysql
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"reflect"
 	"strings"
 	"time"
 
@@ -160,6 +162,32 @@ func (m *MySQl) Select(data interface{}, query string, args ...interface{}) (err
 	return m.DB().Select(data, queryString, bindings...)
 }
 
+// Queryx 查询多条数据，返回 *sqlx.Rows 由调用方逐行读取
+func (m *MySQl) Queryx(query string, args ...interface{}) (rows *sqlx.Rows, err error) {
+	var (
+		queryString string
+		bindings    []interface{}
+	)
+
+	queryString, bindings, err = sqlx.In(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// 记录日志
+	defer func(start time.Time) {
+		m.logger(&QueryParams{
+			Query: queryString,
+			Args:  bindings,
+			Error: err,
+			Start: start,
+			End:   time.Now(),
+		})
+	}(time.Now())
+
+	return m.DB().Queryx(queryString, bindings...)
+}
+
 // Builder 获取查询对象
 func (m *MySQl) Builder(data interface{}) *Builder {
 	return NewBuilder(m, data)
@@ -167,9 +195,46 @@ func (m *MySQl) Builder(data interface{}) *Builder {
 
 // Find 查询一条数据
 func (m *MySQl) Find(model Model, zeroColumn ...string) (err error) {
-	where, args := ToQueryWhere(model, nil, zeroColumn)
+	where, args := findWhere(model, zeroColumn)
+	if len(where) == 0 {
+		return fmt.Errorf("find needs non-zero fields of %T", model)
+	}
+
 	query := fmt.Sprintf("SELECT * FROM `%s` WHERE %s LIMIT 1", model.TableName(), strings.Join(where, " AND "))
-	return m.Get(model, query, args...)
+	if err = m.Get(model, query, args...); err != nil {
+		return err
+	}
+
+	return found(m, model)
+}
+
+// findWhere 使用模型不为零值的字段查询，软删除的数据不查询，没有查询字段时返回空的查询条件
+func findWhere(model Model, zeroColumn []string) ([]string, []interface{}) {
+	where, args := ToQueryWhere(model, nil, zeroColumn)
+	if len(where) == 0 {
+		return nil, nil
+	}
+
+	if deletedAt := GetDeletedAtColumnName(model); deletedAt != "" {
+		where = append(where, fmt.Sprintf("`%s` IS NULL", deletedAt))
+	}
+
+	return where, args
+}
+
+// Refresh 使用主键重新查询数据，获取数据库默认值、生成列和触发器修改的值，软删除的数据也会查询
+func (m *MySQl) Refresh(model Model) error {
+	if hasZeroPK(model) {
+		return fmt.Errorf("refresh needs a non-zero primary key of %T", model)
+	}
+
+	where, args := pkWhere(model)
+	query := fmt.Sprintf("SELECT * FROM `%s` WHERE %s LIMIT 1", model.TableName(), where)
+	if err := m.Get(model, query, args...); err != nil {
+		return err
+	}
+
+	return found(m, model)
 }
 
 // FindAll 查询多条数据
@@ -179,23 +244,61 @@ func (m *MySQl) FindAll(models interface{}, where string, args ...interface{}) e
 		panic(err.Error())
 	}
 
+	// 软删除的数据不查询
+	if deletedAt := GetDeletedAtColumnName(model); deletedAt != "" {
+		if where != "" {
+			where = fmt.Sprintf("(%s) AND `%s` IS NULL", where, deletedAt)
+		} else {
+			where = fmt.Sprintf("`%s` IS NULL", deletedAt)
+		}
+	}
+
 	if where != "" {
 		where = "WHERE " + where
 	}
 
-	return m.Select(models, fmt.Sprintf("SELECT * FROM `%s` %s", model.TableName(), where), args...)
+	if err := m.Select(models, fmt.Sprintf("SELECT * FROM `%s` %s", model.TableName(), where), args...); err != nil {
+		return err
+	}
+
+	return found(m, models)
 }
 
 // Create 创建数据
-func (m *MySQl) Create(model Model, zeroColumn ...string) (err error) {
-	pk := model.PK()
+func (m *MySQl) Create(model Model, zeroColumn ...string) error {
+	if err := beforeCreate(m, model); err != nil {
+		return err
+	}
+
+	if _, err := m.insert(model, zeroColumn); err != nil {
+		return err
+	}
+
+	if IsAutoRefresh(model) {
+		if err := m.Refresh(model); err != nil {
+			return err
+		}
+	}
+
+	SyncOriginal(model)
+	return afterCreate(m, model)
+}
+
+// insert 新增数据并赋值自增主键，主键不为零值时会一起新增，返回影响的行数
+// onDuplicate 不为空时使用 INSERT ... ON DUPLICATE KEY UPDATE，例如：`status` = VALUES(`status`)
+func (m *MySQl) insert(model Model, zeroColumn []string, onDuplicate ...string) (row int64, err error) {
+	if err = generateKey(model); err != nil {
+		return 0, err
+	}
+
+	pks := GetPKColumnNames(model)
 	SetCreateAutoTimestamps(model)
 	columns := StructColumns(model, "db")
 	fields := make([]string, 0)
 	bind := make([]string, 0)
 	bindValue := make([]interface{}, 0)
 	for _, value := range columns {
-		if value.Name != pk && (!value.IsZero || InStringSlice(zeroColumn, value.Name)) {
+		if value.insertable(zeroColumn) && !(value.IsZero && InStringSlice(pks, value.Name)) {
 			fields = append(fields, "`"+value.Name+"`")
 			bind = append(bind, "?")
 			bindValue = append(bindValue, value.Value)
@@ -209,6 +312,10 @@ func (m *MySQl) Create(model Model, zeroColumn ...string) (err error) {
 		strings.Join(bind, ", "),
 	)
 
+	if len(onDuplicate) > 0 {
+		query += " ON DUPLICATE KEY UPDATE " + strings.Join(onDuplicate, ", ")
+	}
+
 	defer func(start time.Time) {
 		m.logger(&QueryParams{
 			Query: query,
@@ -223,7 +330,11 @@ func (m *MySQl) Create(model Model, zeroColumn ...string) (err error) {
 	var result sql.Result
 	result, err = m.DB().Exec(query, bindValue...)
 	if err != nil {
-		return err
+		return 0, err
+	}
+
+	if row, err = result.RowsAffected(); err != nil {
+		return 0, err
 	}
 
 	// 获取自增ID
@@ -232,23 +343,204 @@ func (m *MySQl) Create(model Model, zeroColumn ...string) (err error) {
 	// 赋值主键值
 	SetPKValue(model, id)
 
-	return err
+	return row, err
 }
 
-// Update 修改数据
+// Update 使用主键修改数据，主键有零值时返回错误
 func (m *MySQl) Update(model Model, zeroColumn ...string) (int64, error) {
-	pk := model.PK()
+	if hasZeroPK(model) {
+		return 0, fmt.Errorf("update needs a non-zero primary key of %T", model)
+	}
+
+	if err := beforeUpdate(m, model); err != nil {
+		return 0, err
+	}
+
 	SetUpdateAutoTimestamps(model)
-	where, args := ToQueryWhere(model, []string{pk}, zeroColumn)
-	args = append(args, GetPKValue(model))
-	return m.Exec(
-		fmt.Sprintf("UPDATE `%s` SET %s WHERE `%s` = ? LIMIT 1", model.TableName(), strings.Join(where, ", "), pk),
-		args...,
+	set, args := ToUpdateColumns(model, m.updateExcept(model), zeroColumn)
+	row, err := m.updateSet(model, set, args)
+	if err != nil {
+		return row, err
+	}
+
+	if IsAutoRefresh(model) {
+		if err := m.Refresh(model); err != nil {
+			return row, err
+		}
+	}
+
+	SyncOriginal(model)
+	return row, afterUpdate(m, model)
+}
+
+// UpdateDirty 只修改和原始值不一样的字段，包括修改为零值的字段，没有修改的字段时不执行SQL
+// 模型需要嵌入 Original，并且查询、新增或者 SyncOriginal 记录了原始值
+func (m *MySQl) UpdateDirty(model Model) (int64, error) {
+	t, ok := model.(tracker)
+	if !ok {
+		return 0, fmt.Errorf("update dirty needs %T to embed Original", model)
+	}
+
+	if t.getOriginal() == nil {
+		return 0, fmt.Errorf("update dirty needs the original values of %T, use Find or SyncOriginal first", model)
+	}
+
+	if hasZeroPK(model) {
+		return 0, fmt.Errorf("update needs a non-zero primary key of %T", model)
+	}
+
+	if err := beforeUpdate(m, model); err != nil {
+		return 0, err
+	}
+
+	SetUpdateAutoTimestamps(model)
+	changes := GetChanges(model)
+	except := SliceToMap(m.updateExcept(model))
+	set, args := make([]string, 0, len(changes)), make([]interface{}, 0, len(changes))
+	for _, column := range StructColumns(model, "db") {
+		if _, ok := changes[column.Name]; ok && !except[column.Name] && !column.ReadOnly && !column.InsertOnly {
+			set = append(set, fmt.Sprintf("`%s` = ?", column.Name))
+			args = append(args, column.Value)
+		}
+	}
+
+	if len(set) == 0 {
+		return 0, nil
+	}
+
+	row, err := m.updateSet(model, set, args)
+	if err != nil {
+		return row, err
+	}
+
+	if IsAutoRefresh(model) {
+		if err := m.Refresh(model); err != nil {
+			return row, err
+		}
+	}
+
+	SyncOriginal(model)
+	return row, afterUpdate(m, model)
+}
+
+// updateExcept 修改数据时不修改的字段：主键和版本号
+func (m *MySQl) updateExcept(model Model) []string {
+	except := GetPKColumnNames(model)
+	if version := GetVersionColumnName(model); version != "" {
+		except = append(except, version)
+	}
+
+	return except
+}
+
+// updateSet 使用主键修改数据，set 为 `name` = ? 格式的修改字段
+// 模型实现了 Versioned 时同时使用版本号修改并且版本号加一，没有修改数据时返回 ErrStaleObject
+func (m *MySQl) updateSet(model Model, set []string, args []interface{}) (int64, error) {
+	condition, conditionArgs := pkWhere(model)
+	args = append(args, conditionArgs...)
+	version := GetVersionColumnName(model)
+	if version == "" {
+		return m.Exec(
+			fmt.Sprintf("UPDATE `%s` SET %s WHERE %s LIMIT 1", model.TableName(), strings.Join(set, ", "), condition),
+			args...,
+		)
+	}
+
+	value, err := getVersionValue(model, version)
+	if err != nil {
+		return 0, err
+	}
+
+	set = append(set, fmt.Sprintf("`%s` = `%s` + 1", version, version))
+	row, err := m.Exec(
+		fmt.Sprintf(
+			"UPDATE `%s` SET %s WHERE %s AND `%s` = ? LIMIT 1",
+			model.TableName(), strings.Join(set, ", "), condition, version,
+		),
+		append(args, value.Interface())...,
 	)
+	if err != nil {
+		return row, err
+	}
+
+	if row == 0 {
+		return 0, ErrStaleObject
+	}
+
+	switch value.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value.SetUint(value.Uint() + 1)
+	default:
+		value.SetInt(value.Int() + 1)
+	}
+
+	return row, nil
 }
 
-// Delete 删除数据
+// Delete 删除数据，模型实现了 SoftDeletes 时为软删除
 func (m *MySQl) Delete(model Model, zeroColumns ...string) (int64, error) {
+	if err := beforeDelete(m, model); err != nil {
+		return 0, err
+	}
+
+	var (
+		row int64
+		err error
+	)
+
+	if GetDeletedAtColumnName(model) == "" {
+		row, err = m.forceDelete(model, zeroColumns)
+	} else {
+		row, err = m.softDelete(model, zeroColumns)
+	}
+
+	if err != nil {
+		return row, err
+	}
+
+	return row, afterDelete(m, model)
+}
+
+// ForceDelete 删除数据，模型实现了 SoftDeletes 时也会物理删除
+func (m *MySQl) ForceDelete(model Model, zeroColumns ...string) (int64, error) {
+	if err := beforeDelete(m, model); err != nil {
+		return 0, err
+	}
+
+	row, err := m.forceDelete(model, zeroColumns)
+	if err != nil {
+		return row, err
+	}
+
+	return row, afterDelete(m, model)
+}
+
+// softDelete 软删除数据，修改删除时间
+func (m *MySQl) softDelete(model Model, zeroColumns []string) (int64, error) {
+	deletedAt := GetDeletedAtColumnName(model)
+	where, args := ToQueryWhere(model, []string{deletedAt}, zeroColumns)
+	timeValue := GetTimestampsValue(model)
+	args = append([]interface{}{timeValue}, args...)
+	row, err := m.Exec(
+		fmt.Sprintf(
+			"UPDATE `%s` SET `%s` = ? WHERE %s AND `%s` IS NULL LIMIT 1",
+			model.TableName(),
+			deletedAt,
+			strings.Join(where, " AND "),
+			deletedAt,
+		),
+		args...,
+	)
+
+	if err == nil && row > 0 {
+		SetStructNameValue(reflect.ValueOf(model).Elem(), deletedAt, timeValue)
+	}
+
+	return row, err
+}
+
+// forceDelete 物理删除数据
+func (m *MySQl) forceDelete(model Model, zeroColumns []string) (int64, error) {
 	where, args := ToQueryWhere(model, nil, zeroColumns)
 	return m.Exec(
 		fmt.Sprintf("DELETE FROM `%s` WHERE %s LIMIT 1", model.TableName(), strings.Join(where, " AND ")),
@@ -256,6 +548,33 @@ func (m *MySQl) Delete(model Model, zeroColumns ...string) (int64, error) {
 	)
 }
 
+// Restore 使用主键恢复软删除的数据，主键有零值时返回错误
+func (m *MySQl) Restore(model Model) (int64, error) {
+	deletedAt := GetDeletedAtColumnName(model)
+	if deletedAt == "" {
+		return 0, errors.New("restore needs a model implementing SoftDeletes")
+	}
+
+	if hasZeroPK(model) {
+		return 0, fmt.Errorf("restore needs a non-zero primary key of %T", model)
+	}
+
+	where, args := pkWhere(model)
+	row, err := m.Exec(
+		fmt.Sprintf("UPDATE `%s` SET `%s` = NULL WHERE %s LIMIT 1", model.TableName(), deletedAt, where),
+		args...,
+	)
+
+	if err == nil && row > 0 {
+		value := structField(reflect.ValueOf(model).Elem(), deletedAt)
+		if value.IsValid() && value.CanSet() {
+			value.Set(reflect.Zero(value.Type()))
+		}
+	}
+
+	return row, err
+}
+
 func (m *MySQl) Exec(query string, args ...interface{}) (i int64, err error) {
 
 	// IN 处理
@@ -3,6 +3,7 @@ package mysql
 import (
 	"database/sql"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -214,3 +215,214 @@ func TestMySQl_Rollback(t *testing.T) {
 	assert.NoError(t, err1)
 	assert.Equal(t, 3, len(user))
 }
+
+func TestMySQl_Queryx(t *testing.T) {
+	mySQL := NewTestMySQL(t, examplePathName, userPathName)
+	rows, err := mySQL.Queryx("SELECT * FROM `user` WHERE `user_id` IN (?)", []int{1, 2})
+	assert.NoError(t, err)
+	defer rows.Close()
+
+	total := 0
+	for rows.Next() {
+		total++
+	}
+
+	assert.Equal(t, 2, total)
+
+	_, err = mySQL.Queryx("SELECT * FROM `user` WHERE `user_id` IN (?)", []int{})
+	assert.Error(t, err)
+}
+
+func TestMySQl_SoftDelete(t *testing.T) {
+	mySQL := NewTestMySQL(t, examplePathName, userPathName)
+	user := &SoftUser{UserId: 1}
+	row, err := mySQL.Delete(user)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), row)
+	assert.NotEqual(t, Time{}, user.DeletedAt)
+
+	// 已经删除的数据查询不到
+	err = mySQL.Find(&SoftUser{UserId: 1})
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	users := make([]*SoftUser, 0)
+	assert.NoError(t, mySQL.FindAll(&users, "`status` = ?", 1))
+	assert.Equal(t, 2, len(users))
+
+	// 恢复
+	row, err = mySQL.Restore(user)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), row)
+	assert.Equal(t, Time{}, user.DeletedAt)
+	assert.NoError(t, mySQL.Find(&SoftUser{UserId: 1}))
+
+	// 物理删除
+	row, err = mySQL.ForceDelete(&SoftUser{UserId: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), row)
+
+	users = make([]*SoftUser, 0)
+	assert.NoError(t, mySQL.Builder(&users).WithTrashed().All())
+	assert.Equal(t, 2, len(users))
+
+	_, err = mySQL.Restore(&User{UserId: 1})
+	assert.Error(t, err)
+}
+
+func Test_findWhere(t *testing.T) {
+	where, args := findWhere(&SoftUser{UserId: 1}, nil)
+	assert.Equal(t, []string{"`user_id` = ?", "`deleted_at` IS NULL"}, where)
+	assert.Equal(t, []interface{}{int64(1)}, args)
+
+	// 没有查询字段时不能只使用软删除条件查询
+	where, _ = findWhere(&SoftUser{}, nil)
+	assert.Empty(t, where)
+	assert.EqualError(t, (&MySQl{}).Find(&SoftUser{}), "find needs non-zero fields of *mysql.SoftUser")
+
+	// 设置了主键时其他不为零值的字段也作为查询条件
+	where, args = findWhere(&TenantUser{TenantId: 1, Name: "bob"}, nil)
+	assert.Equal(t, []string{"`tenant_id` = ?", "`name` = ?"}, where)
+	assert.Equal(t, []interface{}{uint64(1), "bob"}, args)
+}
+
+func TestMySQl_RestoreErr(t *testing.T) {
+	_, err := (&MySQl{}).Restore(&User{UserId: 1})
+	assert.EqualError(t, err, "restore needs a model implementing SoftDeletes")
+
+	_, err = (&MySQl{}).Restore(&SoftUser{Username: "test"})
+	assert.EqualError(t, err, "restore needs a non-zero primary key of *mysql.SoftUser")
+}
+
+func TestMySQl_UpdateZeroPK(t *testing.T) {
+	_, err := (&MySQl{}).Update(&TenantUser{UserId: 2, Name: "bob"})
+	assert.EqualError(t, err, "update needs a non-zero primary key of *mysql.TenantUser")
+
+	_, err = (&MySQl{}).Update(&User{Username: "bob"})
+	assert.EqualError(t, err, "update needs a non-zero primary key of *mysql.User")
+}
+
+func TestMySQl_UpdateVersion(t *testing.T) {
+	mySQL := NewTestMySQL(t, examplePathName, userPathName)
+	first, second := &VersionUser{UserId: 1}, &VersionUser{UserId: 1}
+	assert.NoError(t, mySQL.Find(first))
+	assert.NoError(t, mySQL.Find(second))
+	assert.Equal(t, 0, first.Version)
+
+	first.Status = 2
+	row, err := mySQL.Update(first)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), row)
+	assert.Equal(t, 1, first.Version)
+
+	// 版本号已经变化，修改失败
+	second.Username = "stale"
+	row, err = mySQL.Update(second)
+	assert.Equal(t, ErrStaleObject, err)
+	assert.Equal(t, int64(0), row)
+	assert.Equal(t, 0, second.Version)
+
+	user := &VersionUser{UserId: 1}
+	assert.NoError(t, mySQL.Find(user))
+	assert.Equal(t, 1, user.Version)
+	assert.Equal(t, "test1", user.Username)
+	assert.Equal(t, 2, user.Status)
+}
+
+type OptionUser struct {
+	baseUser
+	Username string `db:"username" json:"username"`
+	Password string `db:"password,omitempty" json:"password"`
+	Status   int    `db:"status,insertonly" json:"status"`
+}
+
+type baseUser struct {
+	UserId    int64 `db:"user_id" json:"user_id"`
+	CreatedAt Time  `db:"created_at" json:"created_at"`
+	UpdatedAt Time  `db:"updated_at" json:"updated_at"`
+}
+
+func (*OptionUser) TableName() string {
+	return "user"
+}
+
+func (*OptionUser) PK() string {
+	return "user_id"
+}
+
+func (*OptionUser) TimestampsValue() interface{} {
+	return Time(time.Now())
+}
+
+func TestMySQl_CreateTagOptions(t *testing.T) {
+	mySQL := NewTestMySQL(t, examplePathName)
+	user := &OptionUser{Username: "option", Password: "123456", Status: 2}
+	assert.NoError(t, mySQL.Create(user))
+	assert.NotEqual(t, int64(0), user.UserId)
+	assert.NotEqual(t, Time{}, user.CreatedAt)
+
+	// insertonly 不修改，omitempty 零值不修改
+	user.Status, user.Password, user.Username = 1, "", "option2"
+	row, err := mySQL.Update(user, "password")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), row)
+
+	found := &OptionUser{baseUser: baseUser{UserId: user.UserId}}
+	assert.NoError(t, mySQL.Find(found))
+	assert.Equal(t, "option2", found.Username)
+	assert.Equal(t, "123456", found.Password)
+	assert.Equal(t, 2, found.Status)
+}
+
+type RefreshUser struct {
+	UserId    int64  `db:"user_id" json:"user_id"`
+	Username  string `db:"username" json:"username"`
+	Password  string `db:"password" json:"password"`
+	Status    int    `db:"status" json:"status"`
+	CreatedAt Time   `db:"created_at" json:"created_at"`
+	UpdatedAt Time   `db:"updated_at" json:"updated_at"`
+}
+
+func (*RefreshUser) TableName() string {
+	return "user"
+}
+
+func (*RefreshUser) PK() string {
+	return "user_id"
+}
+
+func (*RefreshUser) AutoRefresh() bool {
+	return true
+}
+
+func (*RefreshUser) TimestampsValue() interface{} {
+	return Time(time.Now())
+}
+
+func TestMySQl_Refresh(t *testing.T) {
+	mySQL := NewTestMySQL(t, examplePathName, userPathName)
+
+	user := &User{UserId: 1}
+	assert.NoError(t, mySQL.Refresh(user))
+	assert.Equal(t, "test1", user.Username)
+
+	_, err := mySQL.Exec("UPDATE `user` SET `status` = 2 WHERE `user_id` = 1")
+	assert.NoError(t, err)
+	assert.NoError(t, mySQL.Refresh(user))
+	assert.Equal(t, 2, user.Status)
+
+	assert.Error(t, mySQL.Refresh(&User{}))
+
+	// 新增后获取数据库默认值
+	refresh := &RefreshUser{Username: "refresh", Password: "123456"}
+	assert.NoError(t, mySQL.Create(refresh))
+	assert.Equal(t, 1, refresh.Status)
+
+	// 修改后获取其他地方修改的值
+	_, err = mySQL.Exec("UPDATE `user` SET `password` = ? WHERE `user_id` = ?", "changed", refresh.UserId)
+	assert.NoError(t, err)
+	refresh.Status, refresh.Password = 2, ""
+	_, err = mySQL.Update(refresh)
+	assert.NoError(t, err)
+	assert.Equal(t, "changed", refresh.Password)
+	assert.Equal(t, 2, refresh.Status)
+}
@@ -0,0 +1,52 @@
+package mysql
+
+// DefaultPageSize 默认每页数量
+const DefaultPageSize = 10
+
+// Pagination 分页信息
+type Pagination struct {
+	// 数据总数
+	Total int64 `json:"total"`
+
+	// 当前页
+	Page int `json:"page"`
+
+	// 每页数量
+	Size int `json:"size"`
+
+	// 最后一页
+	LastPage int `json:"last_page"`
+
+	// 是否还有下一页
+	HasMore bool `json:"has_more"`
+}
+
+// NewPagination 创建分页信息，page 小于等于0 时为第一页，size 小于等于0 时使用默认每页数量
+func NewPagination(page, size int) *Pagination {
+	if page <= 0 {
+		page = 1
+	}
+
+	if size <= 0 {
+		size = DefaultPageSize
+	}
+
+	return &Pagination{Page: page, Size: size}
+}
+
+// SetTotal 设置总数，并计算最后一页和是否还有下一页
+func (p *Pagination) SetTotal(total int64) *Pagination {
+	p.Total = total
+	p.LastPage = int((total + int64(p.Size) - 1) / int64(p.Size))
+	if p.LastPage < 1 {
+		p.LastPage = 1
+	}
+
+	p.HasMore = p.Page < p.LastPage
+	return p
+}
+
+// Offset 查询偏移量
+func (p *Pagination) Offset() int {
+	return (p.Page - 1) * p.Size
+}
@@ -0,0 +1,43 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPagination(t *testing.T) {
+	p := NewPagination(0, 0)
+	assert.Equal(t, 1, p.Page)
+	assert.Equal(t, DefaultPageSize, p.Size)
+	assert.Equal(t, 0, p.Offset())
+
+	p = NewPagination(3, 20)
+	assert.Equal(t, 40, p.Offset())
+}
+
+func TestPagination_SetTotal(t *testing.T) {
+	tests := []struct {
+		name     string
+		page     int
+		size     int
+		total    int64
+		lastPage int
+		hasMore  bool
+	}{
+		{name: "没有数据", page: 1, size: 10, total: 0, lastPage: 1, hasMore: false},
+		{name: "刚好一页", page: 1, size: 10, total: 10, lastPage: 1, hasMore: false},
+		{name: "还有下一页", page: 1, size: 10, total: 11, lastPage: 2, hasMore: true},
+		{name: "最后一页", page: 2, size: 10, total: 11, lastPage: 2, hasMore: false},
+		{name: "超出最后一页", page: 5, size: 10, total: 11, lastPage: 2, hasMore: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPagination(tt.page, tt.size).SetTotal(tt.total)
+			assert.Equal(t, tt.total, p.Total)
+			assert.Equal(t, tt.lastPage, p.LastPage)
+			assert.Equal(t, tt.hasMore, p.HasMore)
+		})
+	}
+}
@@ -0,0 +1,360 @@
+package mysql
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	relationHasOne     = "HasOne"
+	relationHasMany    = "HasMany"
+	relationBelongsTo  = "BelongsTo"
+	relationManyToMany = "ManyToMany"
+)
+
+// Relation 模型关联关系，使用 HasOne、HasMany、BelongsTo、ManyToMany 创建
+type Relation struct {
+	kind string
+
+	// 当前模型的关联字段
+	parentKey string
+
+	// 关联模型的关联字段
+	relatedKey string
+
+	// 多对多关联的中间表和字段
+	pivot           string
+	pivotParentKey  string
+	pivotRelatedKey string
+}
+
+// Relations 模型关联定义，key 为结构体字段名称，关联字段需要设置 db:"-"，例如：
+//
+//	func (*User) Relations() map[string]*Relation {
+//		return map[string]*Relation{
+//			"Orders": HasMany("user_id", ""),
+//		}
+//	}
+type Relations interface {
+	Relations() map[string]*Relation
+}
+
+// HasOne 一对一，关联模型的 foreignKey 等于当前模型的 localKey，localKey 为空时使用主键
+func HasOne(foreignKey, localKey string) *Relation {
+	return &Relation{kind: relationHasOne, parentKey: localKey, relatedKey: foreignKey}
+}
+
+// HasMany 一对多，关联模型的 foreignKey 等于当前模型的 localKey，localKey 为空时使用主键
+func HasMany(foreignKey, localKey string) *Relation {
+	return &Relation{kind: relationHasMany, parentKey: localKey, relatedKey: foreignKey}
+}
+
+// BelongsTo 从属，当前模型的 foreignKey 等于关联模型的 ownerKey，ownerKey 为空时使用关联模型主键
+func BelongsTo(foreignKey, ownerKey string) *Relation {
+	return &Relation{kind: relationBelongsTo, parentKey: foreignKey, relatedKey: ownerKey}
+}
+
+// ManyToMany 多对多，中间表 pivot 的 foreignPivotKey 对应当前模型主键，relatedPivotKey 对应关联模型主键
+func ManyToMany(pivot, foreignPivotKey, relatedPivotKey string) *Relation {
+	return &Relation{
+		kind:            relationManyToMany,
+		pivot:           pivot,
+		pivotParentKey:  foreignPivotKey,
+		pivotRelatedKey: relatedPivotKey,
+	}
+}
+
+// With 预加载关联数据，One、All、Pagination 查询后使用 IN 批量查询关联数据
+// 嵌套关联使用 . 分隔，例如：With("Orders", "Orders.Items")
+func (b *Builder) With(relations ...string) *Builder {
+	b.with = append(b.with, relations...)
+	return b
+}
+
+// eagerLoad 查询后加载预加载的关联数据
+func (b *Builder) eagerLoad() error {
+	if len(b.with) == 0 {
+		return nil
+	}
+
+	parents := relationParents(b.data)
+	if len(parents) == 0 {
+		return nil
+	}
+
+	names, nested := parseWith(b.with)
+	for _, name := range names {
+		if err := loadRelation(b.db, parents, name, nested[name]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseWith 按第一层关联名称分组，返回关联名称和对应的嵌套关联
+func parseWith(with []string) ([]string, map[string][]string) {
+	names := make([]string, 0, len(with))
+	nested := make(map[string][]string, len(with))
+	for _, relation := range with {
+		name, child := relation, ""
+		if index := strings.Index(relation, "."); index >= 0 {
+			name, child = relation[:index], relation[index+1:]
+		}
+
+		if _, ok := nested[name]; !ok {
+			names = append(names, name)
+			nested[name] = make([]string, 0)
+		}
+
+		if child != "" {
+			nested[name] = append(nested[name], child)
+		}
+	}
+
+	return names, nested
+}
+
+// relationParents 获取需要加载关联的结构体，data 可以是结构体指针或者 slice 指针
+func relationParents(data interface{}) []reflect.Value {
+	value := reflect.Indirect(reflect.ValueOf(data))
+	if value.Kind() == reflect.Struct {
+		return []reflect.Value{value}
+	}
+
+	if value.Kind() != reflect.Slice {
+		return nil
+	}
+
+	parents := make([]reflect.Value, 0, value.Len())
+	for i, length := 0, value.Len(); i < length; i++ {
+		item := value.Index(i)
+		if item.Kind() == reflect.Ptr {
+			if item.IsNil() {
+				continue
+			}
+
+			item = item.Elem()
+		}
+
+		if item.Kind() != reflect.Struct {
+			return nil
+		}
+
+		parents = append(parents, item)
+	}
+
+	return parents
+}
+
+// loadRelation 批量查询一个关联的数据并赋值到结构体字段
+func loadRelation(db *MySQl, parents []reflect.Value, name string, nested []string) error {
+	parentType := parents[0].Type()
+	parentModel, ok := parents[0].Addr().Interface().(Model)
+	if !ok {
+		return fmt.Errorf("%s need to implement Model", parentType)
+	}
+
+	relations, ok := parentModel.(Relations)
+	if !ok {
+		return fmt.Errorf("%s need to implement Relations", parentType)
+	}
+
+	relation := relations.Relations()[name]
+	if relation == nil {
+		return fmt.Errorf("relation %s is not defined on %s", name, parentType)
+	}
+
+	field, ok := parentType.FieldByName(name)
+	if !ok {
+		return fmt.Errorf("relation %s has no field on %s", name, parentType)
+	}
+
+	// 关联字段可以是 T、*T、[]T、[]*T
+	many := field.Type.Kind() == reflect.Slice
+	relatedType := field.Type
+	if many {
+		relatedType = relatedType.Elem()
+	}
+
+	if relatedType.Kind() == reflect.Ptr {
+		relatedType = relatedType.Elem()
+	}
+
+	related, ok := reflect.New(relatedType).Interface().(Model)
+	if !ok {
+		return fmt.Errorf("relation %s: %s need to implement Model", name, relatedType)
+	}
+
+	parentKey, relatedKey := relation.parentKey, relation.relatedKey
+	switch relation.kind {
+	case relationBelongsTo:
+		if relatedKey == "" {
+			relatedKey = related.PK()
+		}
+	case relationManyToMany:
+		parentKey, relatedKey = parentModel.PK(), related.PK()
+	default:
+		if parentKey == "" {
+			parentKey = parentModel.PK()
+		}
+	}
+
+	keys, err := relationKeys(parents, parentKey)
+	if err != nil || len(keys) == 0 {
+		return err
+	}
+
+	// 多对多先查询中间表，得到当前模型对应的关联模型主键
+	var pivots map[string][]string
+	if relation.kind == relationManyToMany {
+		if pivots, keys, err = loadPivot(db, relation, keys); err != nil {
+			return err
+		}
+	}
+
+	results := reflect.New(reflect.SliceOf(reflect.PtrTo(relatedType)))
+	if len(keys) > 0 {
+		if err := db.Builder(results.Interface()).WhereIn(relatedKey, keys).With(nested...).All(); err != nil {
+			return err
+		}
+	}
+
+	// 关联数据按关联字段的值分组
+	dictionary := make(map[string][]reflect.Value)
+	for i, length := 0, results.Elem().Len(); i < length; i++ {
+		item := results.Elem().Index(i)
+		value, err := fieldByColumn(item.Elem(), relatedKey)
+		if err != nil {
+			return err
+		}
+
+		key := relationKey(value.Interface())
+		dictionary[key] = append(dictionary[key], item)
+	}
+
+	for _, parent := range parents {
+		value, _ := fieldByColumn(parent, parentKey)
+		key := relationKey(value.Interface())
+		matched := dictionary[key]
+		if pivots != nil {
+			matched = make([]reflect.Value, 0, len(pivots[key]))
+			for _, relatedID := range pivots[key] {
+				matched = append(matched, dictionary[relatedID]...)
+			}
+		}
+
+		setRelation(parent.FieldByIndex(field.Index), matched, many)
+	}
+
+	return nil
+}
+
+// loadPivot 查询多对多中间表，返回当前模型主键对应的关联模型主键，以及去重后的关联模型主键
+func loadPivot(db *MySQl, relation *Relation, keys []interface{}) (map[string][]string, []interface{}, error) {
+	query, args, err := NewBuilder(db, nil).
+		Table(relation.pivot).
+		Select(relation.pivotParentKey, relation.pivotRelatedKey).
+		WhereIn(relation.pivotParentKey, keys).
+		ToSQL()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := db.Queryx(query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer rows.Close()
+
+	pivots := make(map[string][]string)
+	relatedKeys := make([]interface{}, 0)
+	exists := make(map[string]bool)
+	for rows.Next() {
+		var parentID, relatedID interface{}
+		if err := rows.Scan(&parentID, &relatedID); err != nil {
+			return nil, nil, err
+		}
+
+		key := relationKey(relatedID)
+		pivots[relationKey(parentID)] = append(pivots[relationKey(parentID)], key)
+		if !exists[key] {
+			exists[key] = true
+			relatedKeys = append(relatedKeys, relatedID)
+		}
+	}
+
+	return pivots, relatedKeys, rows.Err()
+}
+
+// relationKeys 获取结构体关联字段去重后的非零值
+func relationKeys(parents []reflect.Value, column string) ([]interface{}, error) {
+	keys := make([]interface{}, 0, len(parents))
+	exists := make(map[string]bool, len(parents))
+	for _, parent := range parents {
+		value, err := fieldByColumn(parent, column)
+		if err != nil {
+			return nil, err
+		}
+
+		key := relationKey(value.Interface())
+		if value.IsZero() || exists[key] {
+			continue
+		}
+
+		exists[key] = true
+		keys = append(keys, value.Interface())
+	}
+
+	return keys, nil
+}
+
+// setRelation 关联数据赋值到结构体字段，没有关联数据时设置为零值
+func setRelation(field reflect.Value, items []reflect.Value, many bool) {
+	if many {
+		slice := reflect.MakeSlice(field.Type(), 0, len(items))
+		for _, item := range items {
+			slice = reflect.Append(slice, relationValue(item, field.Type().Elem()))
+		}
+
+		field.Set(slice)
+		return
+	}
+
+	if len(items) == 0 {
+		field.Set(reflect.Zero(field.Type()))
+		return
+	}
+
+	field.Set(relationValue(items[0], field.Type()))
+}
+
+// relationValue 关联数据 *T 转换为字段需要的 T 或者 *T
+func relationValue(item reflect.Value, typ reflect.Type) reflect.Value {
+	if typ.Kind() == reflect.Ptr {
+		return item
+	}
+
+	return item.Elem()
+}
+
+// fieldByColumn 获取字段名称对应的结构体字段
+func fieldByColumn(value reflect.Value, column string) (reflect.Value, error) {
+	field := structField(value, column)
+	if !field.IsValid() {
+		return field, fmt.Errorf("column %s has no field on %s", column, value.Type())
+	}
+
+	return field, nil
+}
+
+// relationKey 关联字段的值转换为字符串，用于匹配关联数据
+func relationKey(value interface{}) string {
+	if v, ok := value.([]byte); ok {
+		return string(v)
+	}
+
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,198 @@
+package mysql
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	relationPathName     = "testdata/relation.sql"
+	relationDataPathName = "testdata/relation_data.sql"
+)
+
+type RelationUser struct {
+	UserId   int64  `db:"user_id" json:"user_id"`
+	Username string `db:"username" json:"username"`
+	Status   int    `db:"status" json:"status"`
+
+	Profile *Profile `db:"-" json:"profile"`
+	Orders  []*Order `db:"-" json:"orders"`
+	Roles   []Role   `db:"-" json:"roles"`
+}
+
+func (*RelationUser) TableName() string {
+	return "user"
+}
+
+func (*RelationUser) PK() string {
+	return "user_id"
+}
+
+func (*RelationUser) Relations() map[string]*Relation {
+	return map[string]*Relation{
+		"Profile": HasOne("user_id", ""),
+		"Orders":  HasMany("user_id", ""),
+		"Roles":   ManyToMany("user_role", "user_id", "role_id"),
+	}
+}
+
+type Profile struct {
+	ProfileId int64  `db:"profile_id" json:"profile_id"`
+	UserId    int64  `db:"user_id" json:"user_id"`
+	Nickname  string `db:"nickname" json:"nickname"`
+}
+
+func (*Profile) TableName() string {
+	return "profile"
+}
+
+func (*Profile) PK() string {
+	return "profile_id"
+}
+
+type Order struct {
+	OrderId int64 `db:"order_id" json:"order_id"`
+	UserId  int64 `db:"user_id" json:"user_id"`
+	Amount  int   `db:"amount" json:"amount"`
+
+	User  *RelationUser `db:"-" json:"user"`
+	Items []*OrderItem  `db:"-" json:"items"`
+}
+
+func (*Order) TableName() string {
+	return "order"
+}
+
+func (*Order) PK() string {
+	return "order_id"
+}
+
+func (*Order) Relations() map[string]*Relation {
+	return map[string]*Relation{
+		"User":  BelongsTo("user_id", ""),
+		"Items": HasMany("order_id", ""),
+	}
+}
+
+type OrderItem struct {
+	ItemId  int64  `db:"item_id" json:"item_id"`
+	OrderId int64  `db:"order_id" json:"order_id"`
+	Name    string `db:"name" json:"name"`
+}
+
+func (*OrderItem) TableName() string {
+	return "order_item"
+}
+
+func (*OrderItem) PK() string {
+	return "item_id"
+}
+
+type Role struct {
+	RoleId int64  `db:"role_id" json:"role_id"`
+	Name   string `db:"name" json:"name"`
+}
+
+func (*Role) TableName() string {
+	return "role"
+}
+
+func (*Role) PK() string {
+	return "role_id"
+}
+
+func TestParseWith(t *testing.T) {
+	names, nested := parseWith([]string{"Orders.Items", "Profile", "Orders", "Orders.User"})
+	assert.Equal(t, []string{"Orders", "Profile"}, names)
+	assert.Equal(t, []string{"Items", "User"}, nested["Orders"])
+	assert.Equal(t, []string{}, nested["Profile"])
+}
+
+func TestRelationParents(t *testing.T) {
+	assert.Equal(t, 1, len(relationParents(&RelationUser{})))
+	assert.Equal(t, 2, len(relationParents(&[]*RelationUser{{}, nil, {}})))
+	assert.Equal(t, 2, len(relationParents(&[]RelationUser{{}, {}})))
+	assert.Nil(t, relationParents(&[]int{1}))
+}
+
+func TestRelationKeys(t *testing.T) {
+	parents := relationParents(&[]*Order{{UserId: 1}, {UserId: 2}, {UserId: 1}, {}})
+	keys, err := relationKeys(parents, "user_id")
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{int64(1), int64(2)}, keys)
+
+	_, err = relationKeys(parents, "not_exists")
+	assert.Error(t, err)
+
+	assert.Equal(t, "1", relationKey(int64(1)))
+	assert.Equal(t, "1", relationKey([]byte("1")))
+}
+
+func TestSetRelation(t *testing.T) {
+	user := &RelationUser{}
+	value := reflect.ValueOf(user).Elem()
+	roles := []reflect.Value{reflect.ValueOf(&Role{RoleId: 1}), reflect.ValueOf(&Role{RoleId: 2})}
+
+	setRelation(value.FieldByName("Roles"), roles, true)
+	assert.Equal(t, []Role{{RoleId: 1}, {RoleId: 2}}, user.Roles)
+
+	setRelation(value.FieldByName("Profile"), []reflect.Value{reflect.ValueOf(&Profile{ProfileId: 1})}, false)
+	assert.Equal(t, &Profile{ProfileId: 1}, user.Profile)
+
+	setRelation(value.FieldByName("Profile"), nil, false)
+	assert.Nil(t, user.Profile)
+
+	setRelation(value.FieldByName("Orders"), nil, true)
+	assert.Equal(t, []*Order{}, user.Orders)
+}
+
+func TestBuilder_With(t *testing.T) {
+	builder := NewBuilder(nil, &[]*RelationUser{}).With("Orders")
+	clone := builder.Clone().With("Orders.Items")
+	assert.Equal(t, []string{"Orders"}, builder.with)
+	assert.Equal(t, []string{"Orders", "Orders.Items"}, clone.with)
+
+	// 关联字段不会作为新增字段
+	for _, column := range StructColumns(&RelationUser{}, "db") {
+		assert.NotEqual(t, "-", column.Name)
+	}
+}
+
+func TestBuilder_EagerLoad(t *testing.T) {
+	mySQL := NewTestMySQL(t, relationPathName, userPathName, relationDataPathName)
+
+	users := make([]*RelationUser, 0)
+	err := mySQL.Builder(&users).With("Profile", "Orders", "Orders.Items", "Roles").OrderBy("user_id", "asc").All()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(users))
+
+	assert.Equal(t, "nick1", users[0].Profile.Nickname)
+	assert.Nil(t, users[1].Profile)
+
+	assert.Equal(t, 2, len(users[0].Orders))
+	assert.Equal(t, 2, len(users[0].Orders[0].Items))
+	assert.Equal(t, 0, len(users[0].Orders[1].Items))
+	assert.Equal(t, 1, len(users[1].Orders))
+	assert.Equal(t, "cherry", users[1].Orders[0].Items[0].Name)
+	assert.Equal(t, 0, len(users[2].Orders))
+
+	assert.Equal(t, 2, len(users[0].Roles))
+	assert.Equal(t, "editor", users[1].Roles[0].Name)
+	assert.Equal(t, 0, len(users[2].Roles))
+
+	order := &Order{}
+	assert.NoError(t, mySQL.Builder(order).Where("order_id", 3).With("User", "User.Orders").One())
+	assert.Equal(t, "test2", order.User.Username)
+	assert.Equal(t, 1, len(order.User.Orders))
+
+	orders := make([]*Order, 0)
+	pagination, err := mySQL.Builder(&orders).With("Items").Pagination(1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), pagination.Total)
+	assert.Equal(t, 2, len(orders[0].Items))
+
+	err = mySQL.Builder(&users).With("NotExists").All()
+	assert.Error(t, err)
+}
@@ -0,0 +1,160 @@
+package mysql
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// schemas 结构体类型的元数据缓存
+var schemas sync.Map
+
+type schemaKey struct {
+	typ     reflect.Type
+	tagName string
+}
+
+// schema 结构体类型的元数据，第一次使用时创建，之后从缓存读取
+type schema struct {
+	typ reflect.Type
+
+	// 数据库字段，已经展开匿名嵌入的结构体
+	columns []*schemaColumn
+
+	// 字段名称对应的结构体字段下标，先按照标签的字段名称查找，再按照 Studly(字段名称) 查找没有指定字段名称的字段，查找后缓存
+	indexes sync.Map
+}
+
+type schemaColumn struct {
+	name       string
+	index      []int
+	tagged     bool
+	readOnly   bool
+	omitEmpty  bool
+	insertOnly bool
+}
+
+// schemaIndex 字段查找结果，字段不存在时 ok 为 false
+type schemaIndex struct {
+	index []int
+	ok    bool
+}
+
+// getSchema 获取结构体类型的元数据
+func getSchema(typ reflect.Type, tagName string) *schema {
+	key := schemaKey{typ: typ, tagName: tagName}
+	if value, ok := schemas.Load(key); ok {
+		return value.(*schema)
+	}
+
+	value, _ := schemas.LoadOrStore(key, newSchema(typ, tagName))
+	return value.(*schema)
+}
+
+func newSchema(typ reflect.Type, tagName string) *schema {
+	return &schema{typ: typ, columns: schemaColumns(typ, tagName, nil, make([]*schemaColumn, 0))}
+}
+
+// schemaColumns 解析结构体的数据库字段，会展开匿名嵌入的结构体，忽略 - 和未导出的字段
+func schemaColumns(typ reflect.Type, tagName string, parent []int, columns []*schemaColumn) []*schemaColumn {
+	for i, length := 0, typ.NumField(); i < length; i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get(tagName)
+		if tag == "-" {
+			continue
+		}
+
+		index := append(append(make([]int, 0, len(parent)+1), parent...), i)
+		name, options := parseColumnTag(tag)
+
+		// 匿名嵌入结构体没有指定字段名称时展开
+		if field.Anonymous && name == "" && isStructType(field.Type) {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+
+			columns = schemaColumns(embedded, tagName, index, columns)
+			continue
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		tagged := name != ""
+		if !tagged {
+			name = strings.ToLower(field.Name)
+		}
+
+		columns = append(columns, &schemaColumn{
+			name:       name,
+			index:      index,
+			tagged:     tagged,
+			readOnly:   options["readonly"],
+			omitEmpty:  options["omitempty"],
+			insertOnly: options["insertonly"],
+		})
+	}
+
+	return columns
+}
+
+// fieldIndex 获取字段名称对应的结构体字段下标，例如：db:"id" 对应 ID，没有指定字段名称时 user_id 对应 UserId
+// 忽略的字段和指定了其他字段名称的字段不会查找到
+func (s *schema) fieldIndex(column string) ([]int, bool) {
+	if value, ok := s.indexes.Load(column); ok {
+		index := value.(schemaIndex)
+		return index.index, index.ok
+	}
+
+	index := schemaIndex{}
+	for _, item := range s.columns {
+		if item.name == column {
+			index = schemaIndex{index: item.index, ok: true}
+			break
+		}
+	}
+
+	if !index.ok {
+		if field, ok := s.typ.FieldByName(Studly(column)); ok {
+			for _, item := range s.columns {
+				if !item.tagged && reflect.DeepEqual(item.index, field.Index) {
+					index = schemaIndex{index: item.index, ok: true}
+					break
+				}
+			}
+		}
+	}
+
+	s.indexes.Store(column, index)
+	return index.index, index.ok
+}
+
+// structField 获取字段名称对应的结构体字段，字段不存在或者嵌入的结构体指针为 nil 时返回无效的 reflect.Value
+func structField(value reflect.Value, column string) reflect.Value {
+	index, ok := getSchema(value.Type(), "db").fieldIndex(column)
+	if !ok {
+		return reflect.Value{}
+	}
+
+	field, _ := fieldByIndex(value, index)
+	return field
+}
+
+// fieldByIndex 按照下标获取字段，嵌入的结构体指针为 nil 时返回 false
+func fieldByIndex(value reflect.Value, index []int) (reflect.Value, bool) {
+	for k, i := range index {
+		if k > 0 && value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				return reflect.Value{}, false
+			}
+
+			value = value.Elem()
+		}
+
+		value = value.Field(i)
+	}
+
+	return value, true
+}
@@ -0,0 +1,164 @@
+package mysql
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSchema(t *testing.T) {
+	typ := reflect.TypeOf(Article{})
+	schema := getSchema(typ, "db")
+	assert.Same(t, schema, getSchema(typ, "db"))
+	assert.NotSame(t, schema, getSchema(typ, "json"))
+
+	names := make([]string, 0, len(schema.columns))
+	for _, column := range schema.columns {
+		names = append(names, column.name)
+	}
+
+	assert.Equal(t, []string{"id", "created_at", "created_by", "title", "summary", "views"}, names)
+	assert.Equal(t, []int{1, 0}, schema.columns[2].index)
+
+	index, ok := schema.fieldIndex("created_by")
+	assert.True(t, ok)
+	assert.Equal(t, []int{1, 0}, index)
+
+	_, ok = schema.fieldIndex("not_exists")
+	assert.False(t, ok)
+	_, ok = schema.fieldIndex("not_exists")
+	assert.False(t, ok)
+}
+
+func TestStructField(t *testing.T) {
+	article := &Article{baseModel: baseModel{Id: 1}}
+	value := reflect.ValueOf(article).Elem()
+	assert.Equal(t, int64(1), structField(value, "id").Interface())
+	assert.False(t, structField(value, "not_exists").IsValid())
+
+	// 嵌入的结构体指针为 nil
+	assert.False(t, structField(value, "created_by").IsValid())
+
+	article.Audit = &Audit{CreatedBy: "admin"}
+	assert.Equal(t, "admin", structField(value, "created_by").Interface())
+}
+
+type TagUser struct {
+	ID        int64  `db:"id"`
+	UserName  string `db:"name"`
+	NickName  string `db:",omitempty"`
+	Version   int    `db:"-"`
+	CreatedAt string
+}
+
+func (*TagUser) TableName() string {
+	return "user"
+}
+
+func (*TagUser) PK() string {
+	return "id"
+}
+
+func TestStructField_Tag(t *testing.T) {
+	user := &TagUser{ID: 1, UserName: "test"}
+	value := reflect.ValueOf(user).Elem()
+
+	// 按照标签的字段名称查找，Studly 查找不到 ID 和 UserName
+	assert.Equal(t, int64(1), structField(value, "id").Interface())
+	assert.Equal(t, "test", structField(value, "name").Interface())
+	assert.Equal(t, int64(1), GetPKValue(user))
+	assert.True(t, SetPKValue(&TagUser{}, 2))
+
+	// 没有指定字段名称时使用 Studly 查找
+	user.NickName, user.CreatedAt = "nick", "2020-11-01"
+	assert.Equal(t, "nick", structField(value, "nick_name").Interface())
+	assert.Equal(t, "2020-11-01", structField(value, "created_at").Interface())
+
+	// 忽略的字段和指定了其他字段名称的字段查找不到
+	assert.False(t, structField(value, "user_name").IsValid())
+	assert.False(t, structField(value, "version").IsValid())
+	assert.False(t, SetStructNameValue(value, "version", 1))
+}
+
+// MissingPKUser 主键没有对应的结构体字段
+type MissingPKUser struct {
+	Name string `db:"name"`
+}
+
+func (*MissingPKUser) TableName() string {
+	return "user"
+}
+
+func (*MissingPKUser) PK() string {
+	return "id"
+}
+
+func TestGetPKValue_Missing(t *testing.T) {
+	assert.Nil(t, GetPKValue(&MissingPKUser{}))
+	assert.Equal(t, []interface{}{nil}, GetPKValues(&MissingPKUser{}))
+	assert.True(t, hasZeroPK(&MissingPKUser{}))
+	assert.False(t, SetPKValue(&MissingPKUser{}, 1))
+}
+
+func TestGetSchema_Concurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			user := &User{UserId: 1}
+			assert.Equal(t, 6, len(StructColumns(user, "db")))
+			assert.Equal(t, int64(1), GetPKValue(user))
+		}()
+	}
+
+	wg.Wait()
+}
+
+func BenchmarkStructColumns(b *testing.B) {
+	user := &User{UserId: 1, Username: "username"}
+	for i := 0; i < b.N; i++ {
+		StructColumns(user, "db")
+	}
+}
+
+// BenchmarkStructColumns_NoCache 每次都解析结构体字段，用于对比缓存的效果
+func BenchmarkStructColumns_NoCache(b *testing.B) {
+	user := &User{UserId: 1, Username: "username"}
+	for i := 0; i < b.N; i++ {
+		value := reflect.ValueOf(user).Elem()
+		for _, column := range newSchema(value.Type(), "db").columns {
+			field, _ := fieldByIndex(value, column.index)
+			_ = &Column{Name: column.name, Value: field.Interface(), IsZero: field.IsZero()}
+		}
+	}
+}
+
+func BenchmarkGetPKValue(b *testing.B) {
+	user := &User{UserId: 1}
+	for i := 0; i < b.N; i++ {
+		GetPKValue(user)
+	}
+}
+
+// BenchmarkGetPKValue_NoCache 每次都使用 Studly 和 FieldByName 查找字段，用于对比缓存的效果
+func BenchmarkGetPKValue_NoCache(b *testing.B) {
+	user := &User{UserId: 1}
+	for i := 0; i < b.N; i++ {
+		reflect.ValueOf(user).Elem().FieldByName(Studly(user.PK())).Interface()
+	}
+}
+
+func BenchmarkSetPKValue(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		SetPKValue(&User{}, 1)
+	}
+}
+
+func BenchmarkSetCreateAutoTimestamps(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		SetCreateAutoTimestamps(&User{})
+	}
+}
@@ -5,6 +5,19 @@ CREATE TABLE `user` (
   `status` tinyint(1) NOT NULL DEFAULT '1' COMMENT '状态 1 启用 2 停用',
   `created_at` datetime NOT NULL COMMENT '创建时间',
   `updated_at` datetime NOT NULL COMMENT '修改时间',
+  `deleted_at` datetime DEFAULT NULL COMMENT '删除时间',
+  `version` int(11) NOT NULL DEFAULT '0' COMMENT '版本号',
   PRIMARY KEY (`user_id`),
   UNIQUE KEY `unq_username` (`username`) COMMENT '名称唯一'
-) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='用户信息表';
\ No newline at end of file
+) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='用户信息表';
+CREATE TABLE `token` (
+  `token_id` char(36) NOT NULL COMMENT '主键 UUID',
+  `name` varchar(32) NOT NULL DEFAULT '' COMMENT '名称',
+  PRIMARY KEY (`token_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='令牌表';
+CREATE TABLE `tenant_user` (
+  `tenant_id` int(11) unsigned NOT NULL COMMENT '租户ID',
+  `user_id` bigint(20) unsigned NOT NULL COMMENT '用户ID',
+  `name` varchar(32) NOT NULL DEFAULT '' COMMENT '名称',
+  PRIMARY KEY (`tenant_id`, `user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='租户用户表';
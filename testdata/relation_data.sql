@@ -0,0 +1,12 @@
+INSERT INTO `profile`(`user_id`, `nickname`) VALUES (1, 'nick1');
+INSERT INTO `order`(`user_id`, `amount`) VALUES (1, 100);
+INSERT INTO `order`(`user_id`, `amount`) VALUES (1, 200);
+INSERT INTO `order`(`user_id`, `amount`) VALUES (2, 300);
+INSERT INTO `order_item`(`order_id`, `name`) VALUES (1, 'apple');
+INSERT INTO `order_item`(`order_id`, `name`) VALUES (1, 'banana');
+INSERT INTO `order_item`(`order_id`, `name`) VALUES (3, 'cherry');
+INSERT INTO `role`(`name`) VALUES ('admin');
+INSERT INTO `role`(`name`) VALUES ('editor');
+INSERT INTO `user_role`(`user_id`, `role_id`) VALUES (1, 1);
+INSERT INTO `user_role`(`user_id`, `role_id`) VALUES (1, 2);
+INSERT INTO `user_role`(`user_id`, `role_id`) VALUES (2, 2);
@@ -62,6 +62,12 @@ func (t Time) Value() (driver.Value, error) {
 }
 
 func (t *Time) Scan(v interface{}) error {
+	// NULL 值
+	if v == nil {
+		*t = Time{}
+		return nil
+	}
+
 	tTime, _ := time.ParseInLocation("2006-01-02 15:04:05", v.(time.Time).Format(DateTimeLayout), loc)
 	*t = Time(tTime)
 	return nil
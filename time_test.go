@@ -46,3 +46,13 @@ func TestTime_Value(t *testing.T) {
 	assert.Equal(t, nil, timeDriver)
 	assert.NoError(t, err)
 }
+
+func TestTime_Scan(t *testing.T) {
+	now := time.Now()
+	tm := Now()
+	assert.NoError(t, tm.Scan(nil))
+	assert.Equal(t, Time{}, tm)
+
+	assert.NoError(t, tm.Scan(now))
+	assert.Equal(t, now.Format(DateTimeLayout), tm.String())
+}
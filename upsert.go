@@ -0,0 +1,300 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// UniqueKeys 模型的唯一键字段，FirstOrCreate、UpdateOrCreate 的查询字段和唯一键完全相同时
+// 使用 INSERT ... ON DUPLICATE KEY UPDATE 保证原子性，否则在事务中使用 SELECT ... FOR UPDATE 查询
+type UniqueKeys interface {
+	UniqueKeys() []string
+}
+
+// GetUniqueKeys 获取唯一键字段，没有实现 UniqueKeys 时返回 nil
+func GetUniqueKeys(model Model) []string {
+	if uniqueKeys, ok := model.(UniqueKeys); ok {
+		return uniqueKeys.UniqueKeys()
+	}
+
+	return nil
+}
+
+// Save 主键为零值时新增数据，否则使用主键修改数据
+// 模型嵌入了 Original 并且记录了原始值时只修改变化的字段
+func (m *MySQl) Save(model Model, zeroColumn ...string) error {
+	if hasZeroPK(model) {
+		return m.Create(model, zeroColumn...)
+	}
+
+	var err error
+	if t, ok := model.(tracker); ok && t.getOriginal() != nil {
+		_, err = m.UpdateDirty(model)
+	} else {
+		_, err = m.Update(model, zeroColumn...)
+	}
+
+	return err
+}
+
+// FirstOrCreate 使用模型不为零值的字段查询一条数据，查询不到时使用 attrs 补充字段后新增数据，没有查询字段时返回错误
+// 查询字段和 UniqueKeys 相同时使用 upsert 新增，唯一键重复时不修改数据，也不执行 AfterCreate
+func (m *MySQl) FirstOrCreate(model Model, attrs map[string]interface{}) error {
+	where, args := findWhere(model, nil)
+	if len(where) == 0 {
+		return fmt.Errorf("find needs non-zero fields of %T", model)
+	}
+
+	if uniqueKeys := GetUniqueKeys(model); isUniqueKeys(uniqueKeys, nonZeroColumns(model)) {
+		if err := m.Find(model); err != sql.ErrNoRows {
+			return err
+		}
+
+		if err := setColumnValues(model, attrs); err != nil {
+			return err
+		}
+
+		// 并发新增时唯一键重复不修改数据，之后重新查询
+		return m.upsert(model, uniqueKeys, nil, mapKeys(attrs))
+	}
+
+	return m.transaction(func(tx *MySQl) error {
+		if err := tx.findForUpdate(model, where, args); err != sql.ErrNoRows {
+			return err
+		}
+
+		if err := setColumnValues(model, attrs); err != nil {
+			return err
+		}
+
+		return tx.Create(model, mapKeys(attrs)...)
+	})
+}
+
+// UpdateOrCreate 使用 match 查询数据，查询到时使用 values 修改数据，否则使用 match 和 values 新增数据，match 不能为空
+// match 的字段和 UniqueKeys 相同并且模型没有实现 BeforeUpdate 时使用 upsert，否则在事务中查询后执行 Create 或者 Update
+func (m *MySQl) UpdateOrCreate(model Model, match, values map[string]interface{}) error {
+	if len(match) == 0 {
+		return fmt.Errorf("update or create needs match values of %T", model)
+	}
+
+	if err := setColumnValues(model, match); err != nil {
+		return err
+	}
+
+	zeroColumn := append(mapKeys(match), mapKeys(values)...)
+	_, hasBeforeUpdate := model.(BeforeUpdate)
+	if uniqueKeys := GetUniqueKeys(model); !hasBeforeUpdate && isUniqueKeys(uniqueKeys, mapKeys(match)) {
+		if err := setColumnValues(model, values); err != nil {
+			return err
+		}
+
+		return m.upsert(model, uniqueKeys, mapKeys(values), zeroColumn)
+	}
+
+	return m.transaction(func(tx *MySQl) error {
+		where, args := mapWhere(match)
+		if deletedAt := GetDeletedAtColumnName(model); deletedAt != "" {
+			where = append(where, fmt.Sprintf("`%s` IS NULL", deletedAt))
+		}
+
+		err := tx.findForUpdate(model, where, args)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+
+		if setErr := setColumnValues(model, values); setErr != nil {
+			return setErr
+		}
+
+		if err == sql.ErrNoRows {
+			return tx.Create(model, zeroColumn...)
+		}
+
+		_, err = tx.Update(model, mapKeys(values)...)
+		return err
+	})
+}
+
+// upsert 使用 INSERT ... ON DUPLICATE KEY UPDATE 新增数据，唯一键重复时修改 updateColumns，然后使用唯一键重新查询数据
+// 执行前不知道是新增还是修改，所以只执行 BeforeCreate，不会执行 BeforeUpdate，新增后执行 AfterCreate，修改后执行 AfterUpdate
+// 修改时版本号加 1，但不检查版本号
+func (m *MySQl) upsert(model Model, uniqueKeys, updateColumns, zeroColumn []string) error {
+	if err := beforeCreate(m, model); err != nil {
+		return err
+	}
+
+	version := GetVersionColumnName(model)
+	onDuplicate := make([]string, 0, len(updateColumns)+2)
+	sort.Strings(updateColumns)
+	for _, column := range updateColumns {
+		if column != version {
+			onDuplicate = append(onDuplicate, fmt.Sprintf("`%s` = VALUES(`%s`)", column, column))
+		}
+	}
+
+	if len(onDuplicate) == 0 {
+		onDuplicate = append(onDuplicate, fmt.Sprintf("`%s` = `%s`", uniqueKeys[0], uniqueKeys[0]))
+	} else {
+		if updatedAt := GetUpdatedAtColumnName(model); IsAutoTimestamps(model) &&
+			structField(reflect.ValueOf(model).Elem(), updatedAt).IsValid() && !InStringSlice(updateColumns, updatedAt) {
+			onDuplicate = append(onDuplicate, fmt.Sprintf("`%s` = VALUES(`%s`)", updatedAt, updatedAt))
+		}
+
+		if version != "" {
+			onDuplicate = append(onDuplicate, fmt.Sprintf("`%s` = `%s` + 1", version, version))
+		}
+	}
+
+	// 影响行数 1 为新增，2 为修改，0 为重复并且没有修改
+	row, err := m.insert(model, zeroColumn, onDuplicate...)
+	if err != nil {
+		return err
+	}
+
+	where := make([]string, 0, len(uniqueKeys))
+	args := make([]interface{}, 0, len(uniqueKeys))
+	value := reflect.ValueOf(model).Elem()
+	for _, column := range uniqueKeys {
+		field := structField(value, column)
+		if !field.IsValid() {
+			return fmt.Errorf("unique key %s has no field on %T", column, model)
+		}
+
+		where = append(where, fmt.Sprintf("`%s` = ?", column))
+		args = append(args, field.Interface())
+	}
+
+	query := fmt.Sprintf("SELECT * FROM `%s` WHERE %s LIMIT 1", model.TableName(), strings.Join(where, " AND "))
+	if err := m.Get(model, query, args...); err != nil {
+		return err
+	}
+
+	if err := found(m, model); err != nil {
+		return err
+	}
+
+	switch row {
+	case 1:
+		return afterCreate(m, model)
+	case 2:
+		return afterUpdate(m, model)
+	}
+
+	return nil
+}
+
+// findForUpdate 在事务中使用 SELECT ... FOR UPDATE 查询一条数据，没有查询条件时返回错误，不会锁住全表
+func (m *MySQl) findForUpdate(model Model, where []string, args []interface{}) error {
+	if len(where) == 0 {
+		return fmt.Errorf("find for update needs where conditions of %T", model)
+	}
+
+	query := fmt.Sprintf("SELECT * FROM `%s` WHERE %s LIMIT 1 FOR UPDATE", model.TableName(), strings.Join(where, " AND "))
+	if err := m.Get(model, query, args...); err != nil {
+		return err
+	}
+
+	return found(m, model)
+}
+
+// transaction 已经在事务中时直接执行，否则开启事务执行
+func (m *MySQl) transaction(fn func(tx *MySQl) error) error {
+	if m.tx != nil {
+		return fn(m)
+	}
+
+	return m.Transaction(fn)
+}
+
+// setColumnValues 按照字段名称设置模型的值，值为 nil 时设置为零值，数字类型可以自动转换
+func setColumnValues(model Model, values map[string]interface{}) error {
+	value := reflect.ValueOf(model).Elem()
+	for column, v := range values {
+		field := structField(value, column)
+		if !field.IsValid() || !field.CanSet() {
+			return fmt.Errorf("column %s has no field on %T", column, model)
+		}
+
+		if v == nil {
+			field.Set(reflect.Zero(field.Type()))
+			continue
+		}
+
+		item := reflect.ValueOf(v)
+		switch {
+		case item.Type().AssignableTo(field.Type()):
+			field.Set(item)
+		case isNumberKind(item.Kind()) && isNumberKind(field.Kind()):
+			field.Set(item.Convert(field.Type()))
+		default:
+			return fmt.Errorf("column %s cannot use %T as %s", column, v, field.Type())
+		}
+	}
+
+	return nil
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Float64
+}
+
+// mapWhere map 转换为相等的查询条件，值为 nil 时查询 IS NULL，按照字段名称排序
+func mapWhere(values map[string]interface{}) ([]string, []interface{}) {
+	keys := mapKeys(values)
+	where := make([]string, 0, len(keys))
+	args := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		if values[key] == nil {
+			where = append(where, fmt.Sprintf("`%s` IS NULL", key))
+			continue
+		}
+
+		where = append(where, fmt.Sprintf("`%s` = ?", key))
+		args = append(args, values[key])
+	}
+
+	return where, args
+}
+
+// isUniqueKeys 查询字段和唯一键是否完全相同，只有这时唯一键重复的数据才是要查询的数据
+func isUniqueKeys(uniqueKeys, columns []string) bool {
+	if len(uniqueKeys) == 0 || len(uniqueKeys) != len(columns) {
+		return false
+	}
+
+	keys := SliceToMap(uniqueKeys)
+	for _, column := range columns {
+		if !keys[column] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// nonZeroColumns 获取模型不为零值的字段名称，和 findWhere 的查询字段一致
+func nonZeroColumns(model Model) []string {
+	columns := make([]string, 0)
+	for _, column := range StructColumns(model, "db") {
+		if !column.IsZero {
+			columns = append(columns, column.Name)
+		}
+	}
+
+	return columns
+}
+
+// mapKeys 获取 map 排序后的 key
+func mapKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
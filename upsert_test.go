@@ -0,0 +1,173 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type UniqueUser struct {
+	UserId    int64  `db:"user_id" json:"user_id"`
+	Username  string `db:"username" json:"username"`
+	Password  string `db:"password" json:"password"`
+	Status    int    `db:"status" json:"status"`
+	CreatedAt Time   `db:"created_at" json:"created_at"`
+	UpdatedAt Time   `db:"updated_at" json:"updated_at"`
+}
+
+func (*UniqueUser) TableName() string {
+	return "user"
+}
+
+func (*UniqueUser) PK() string {
+	return "user_id"
+}
+
+func (*UniqueUser) UniqueKeys() []string {
+	return []string{"username"}
+}
+
+func (*UniqueUser) TimestampsValue() interface{} {
+	return Time(time.Now())
+}
+
+func TestGetUniqueKeys(t *testing.T) {
+	assert.Equal(t, []string{"username"}, GetUniqueKeys(&UniqueUser{}))
+	assert.Nil(t, GetUniqueKeys(&User{}))
+}
+
+func TestSetColumnValues(t *testing.T) {
+	user := &UniqueUser{Password: "123456"}
+	err := setColumnValues(user, map[string]interface{}{
+		"user_id":  1,
+		"username": "test",
+		"status":   int64(2),
+		"password": nil,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &UniqueUser{UserId: 1, Username: "test", Status: 2}, user)
+
+	assert.Error(t, setColumnValues(user, map[string]interface{}{"not_exists": 1}))
+	assert.Error(t, setColumnValues(user, map[string]interface{}{"username": 1}))
+	assert.Error(t, setColumnValues(user, map[string]interface{}{"status": "1"}))
+}
+
+func TestMapWhere(t *testing.T) {
+	where, args := mapWhere(map[string]interface{}{"username": "test", "status": 1, "deleted_at": nil})
+	assert.Equal(t, []string{"`deleted_at` IS NULL", "`status` = ?", "`username` = ?"}, where)
+	assert.Equal(t, []interface{}{1, "test"}, args)
+	assert.Equal(t, []string{}, mapKeys(nil))
+}
+
+func TestMySQl_Save(t *testing.T) {
+	mySQL := NewTestMySQL(t, examplePathName, userPathName)
+
+	user := &User{Username: "save", Password: "123456", Status: 1}
+	assert.NoError(t, mySQL.Save(user))
+	assert.NotEqual(t, int64(0), user.UserId)
+
+	user.Status = 0
+	assert.NoError(t, mySQL.Save(user, "status"))
+	found := &User{UserId: user.UserId}
+	assert.NoError(t, mySQL.Find(found))
+	assert.Equal(t, 0, found.Status)
+
+	// 嵌入 Original 只修改变化的字段
+	dirty := &DirtyUser{UserId: 1}
+	assert.NoError(t, mySQL.Find(dirty))
+	dirty.Status = 2
+	assert.NoError(t, mySQL.Save(dirty))
+	assert.False(t, IsDirty(dirty))
+}
+
+func TestMySQl_FirstOrCreate(t *testing.T) {
+	mySQL := NewTestMySQL(t, examplePathName, userPathName)
+
+	// 唯一键
+	user := &UniqueUser{Username: "test1"}
+	assert.NoError(t, mySQL.FirstOrCreate(user, map[string]interface{}{"password": "new"}))
+	assert.Equal(t, int64(1), user.UserId)
+	assert.Equal(t, "v123456", user.Password)
+
+	user = &UniqueUser{Username: "first"}
+	assert.NoError(t, mySQL.FirstOrCreate(user, map[string]interface{}{"password": "new", "status": 2}))
+	assert.NotEqual(t, int64(0), user.UserId)
+	assert.Equal(t, 2, user.Status)
+
+	// 事务中查询
+	other := &User{Username: "test2"}
+	assert.NoError(t, mySQL.FirstOrCreate(other, map[string]interface{}{"password": "new"}))
+	assert.Equal(t, int64(2), other.UserId)
+
+	other = &User{Username: "second"}
+	assert.NoError(t, mySQL.FirstOrCreate(other, map[string]interface{}{"password": "new"}))
+	assert.NotEqual(t, int64(0), other.UserId)
+	assert.Equal(t, "new", other.Password)
+}
+
+func TestMySQl_UpdateOrCreate(t *testing.T) {
+	mySQL := NewTestMySQL(t, examplePathName, userPathName)
+
+	// 唯一键
+	user := &UniqueUser{}
+	err := mySQL.UpdateOrCreate(user, map[string]interface{}{"username": "test1"}, map[string]interface{}{"status": 0})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), user.UserId)
+	assert.Equal(t, 0, user.Status)
+	assert.Equal(t, "v123456", user.Password)
+
+	user = &UniqueUser{Password: "123456"}
+	err = mySQL.UpdateOrCreate(user, map[string]interface{}{"username": "upsert"}, map[string]interface{}{"status": 2})
+	assert.NoError(t, err)
+	assert.NotEqual(t, int64(0), user.UserId)
+	assert.Equal(t, 2, user.Status)
+
+	// match 不是唯一键时在事务中查询，test1 的状态不是 2 需要新增，唯一键重复返回错误，不会修改 test1
+	user = &UniqueUser{}
+	err = mySQL.UpdateOrCreate(user, map[string]interface{}{"username": "test1", "status": 2}, map[string]interface{}{"password": "changed"})
+	assert.Error(t, err)
+
+	found := &UniqueUser{Username: "test1"}
+	assert.NoError(t, mySQL.Find(found))
+	assert.Equal(t, "v123456", found.Password)
+
+	// 事务中查询
+	other := &User{}
+	err = mySQL.UpdateOrCreate(other, map[string]interface{}{"username": "test2"}, map[string]interface{}{"status": 0})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), other.UserId)
+	assert.Equal(t, 0, other.Status)
+
+	other = &User{Password: "123456"}
+	err = mySQL.UpdateOrCreate(other, map[string]interface{}{"username": "tx"}, map[string]interface{}{"status": 2})
+	assert.NoError(t, err)
+	assert.NotEqual(t, int64(0), other.UserId)
+
+	err = mySQL.UpdateOrCreate(&User{}, map[string]interface{}{"not_exists": 1}, nil)
+	assert.Error(t, err)
+}
+
+func TestMySQl_UpsertEmptyWhere(t *testing.T) {
+	mySQL := &MySQl{}
+
+	// 没有查询条件时不能查询任意一条数据
+	assert.EqualError(t, mySQL.FirstOrCreate(&UniqueUser{}, nil), "find needs non-zero fields of *mysql.UniqueUser")
+	assert.EqualError(t, mySQL.FirstOrCreate(&User{}, nil), "find needs non-zero fields of *mysql.User")
+
+	err := mySQL.UpdateOrCreate(&User{}, nil, map[string]interface{}{"status": 1})
+	assert.EqualError(t, err, "update or create needs match values of *mysql.User")
+
+	assert.EqualError(t, mySQL.findForUpdate(&User{}, nil, nil), "find for update needs where conditions of *mysql.User")
+}
+
+func Test_isUniqueKeys(t *testing.T) {
+	assert.True(t, isUniqueKeys([]string{"username"}, []string{"username"}))
+	assert.True(t, isUniqueKeys([]string{"tenant_id", "username"}, []string{"username", "tenant_id"}))
+	assert.False(t, isUniqueKeys([]string{"username"}, []string{"username", "status"}))
+	assert.False(t, isUniqueKeys([]string{"tenant_id", "username"}, []string{"username"}))
+	assert.False(t, isUniqueKeys([]string{"username"}, []string{"status"}))
+	assert.False(t, isUniqueKeys(nil, []string{"username"}))
+
+	assert.Equal(t, []string{"username", "status"}, nonZeroColumns(&UniqueUser{Username: "test", Status: 1}))
+}
@@ -75,9 +75,9 @@ func ToQueryWhere(data interface{}, exceptColumn, joinColumn []string) ([]string
 			continue
 		}
 
-		// 需要加入
+		// 需要加入，omitempty 的字段零值时不加入
 		_, isJoin := join[v.Name]
-		if !v.IsZero || isJoin {
+		if !v.IsZero || (isJoin && !v.OmitEmpty) {
 			where = append(where, fmt.Sprintf("`%s` = ?", v.Name))
 			args = append(args, v.Value)
 		}
@@ -86,6 +86,21 @@ func ToQueryWhere(data interface{}, exceptColumn, joinColumn []string) ([]string
 	return where, args
 }
 
+// ToUpdateColumns 获取修改数据的字段，忽略 readonly、insertonly 的字段，zeroColumn 为需要修改零值的字段
+func ToUpdateColumns(data interface{}, exceptColumn, zeroColumn []string) ([]string, []interface{}) {
+	columns := make([]string, 0)
+	args := make([]interface{}, 0)
+	except := SliceToMap(exceptColumn)
+	for _, v := range StructColumns(data, "db") {
+		if !except[v.Name] && v.updatable(zeroColumn) {
+			columns = append(columns, fmt.Sprintf("`%s` = ?", v.Name))
+			args = append(args, v.Value)
+		}
+	}
+
+	return columns, args
+}
+
 func getDsn(name string) string {
 	return fmt.Sprintf(
 		"%s:%s@tcp(%s:3306)",
@@ -3,6 +3,7 @@ package mysql
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -179,3 +180,14 @@ func TestToQueryWhere1(t *testing.T) {
 	assert.Equal(t, 3, len(args))
 	fmt.Println(where, args)
 }
+
+func TestToUpdateColumns(t *testing.T) {
+	article := &Article{baseModel: baseModel{Id: 1, CreatedAt: Time(time.Now())}, Audit: &Audit{CreatedBy: "admin"}, Title: "title"}
+	columns, args := ToUpdateColumns(article, []string{"id"}, []string{"summary", "views"})
+	assert.Equal(t, []string{"`title` = ?", "`views` = ?"}, columns)
+	assert.Equal(t, []interface{}{"title", 0}, args)
+
+	where, args := ToQueryWhere(article, nil, []string{"summary"})
+	assert.Equal(t, []string{"`id` = ?", "`created_at` = ?", "`created_by` = ?", "`title` = ?"}, where)
+	assert.Equal(t, 4, len(args))
+}
@@ -0,0 +1,235 @@
+package mysql
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// likeReplacer LIKE 查询需要转义的字符
+var likeReplacer = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// columnOperators 字段比较支持的运算符
+var columnOperators = map[string]bool{
+	"=":   true,
+	"!=":  true,
+	"<>":  true,
+	">":   true,
+	">=":  true,
+	"<":   true,
+	"<=":  true,
+	"<=>": true,
+}
+
+// EscapeLike 转义 LIKE 查询中的通配符 % 和 _
+func EscapeLike(value string) string {
+	return likeReplacer.Replace(value)
+}
+
+// WhereNull 字段为 NULL
+func (b *Builder) WhereNull(column string) *Builder {
+	return b.whereNull("AND", column, false)
+}
+
+// OrWhereNull 或者字段为 NULL
+func (b *Builder) OrWhereNull(column string) *Builder {
+	return b.whereNull("OR", column, false)
+}
+
+// WhereNotNull 字段不为 NULL
+func (b *Builder) WhereNotNull(column string) *Builder {
+	return b.whereNull("AND", column, true)
+}
+
+// OrWhereNotNull 或者字段不为 NULL
+func (b *Builder) OrWhereNotNull(column string) *Builder {
+	return b.whereNull("OR", column, true)
+}
+
+// WhereLike 模糊查询，会转义 value 中的通配符，查询包含 value 的数据
+func (b *Builder) WhereLike(column, value string) *Builder {
+	return b.whereLike("AND", column, value, false)
+}
+
+// OrWhereLike 或者模糊查询
+func (b *Builder) OrWhereLike(column, value string) *Builder {
+	return b.whereLike("OR", column, value, false)
+}
+
+// WhereNotLike 模糊查询不包含 value 的数据
+func (b *Builder) WhereNotLike(column, value string) *Builder {
+	return b.whereLike("AND", column, value, true)
+}
+
+// OrWhereNotLike 或者模糊查询不包含 value 的数据
+func (b *Builder) OrWhereNotLike(column, value string) *Builder {
+	return b.whereLike("OR", column, value, true)
+}
+
+// WhereIn 字段在 values 中，values 为空时查询条件不成立
+func (b *Builder) WhereIn(column string, values interface{}) *Builder {
+	return b.whereIn("AND", column, values, false)
+}
+
+// OrWhereIn 或者字段在 values 中
+func (b *Builder) OrWhereIn(column string, values interface{}) *Builder {
+	return b.whereIn("OR", column, values, false)
+}
+
+// WhereNotIn 字段不在 values 中，values 为空时查询条件恒成立
+func (b *Builder) WhereNotIn(column string, values interface{}) *Builder {
+	return b.whereIn("AND", column, values, true)
+}
+
+// OrWhereNotIn 或者字段不在 values 中
+func (b *Builder) OrWhereNotIn(column string, values interface{}) *Builder {
+	return b.whereIn("OR", column, values, true)
+}
+
+// WhereBetween 字段在 start 和 end 之间
+func (b *Builder) WhereBetween(column string, start, end interface{}) *Builder {
+	return b.whereBetween("AND", column, start, end, false)
+}
+
+// OrWhereBetween 或者字段在 start 和 end 之间
+func (b *Builder) OrWhereBetween(column string, start, end interface{}) *Builder {
+	return b.whereBetween("OR", column, start, end, false)
+}
+
+// WhereNotBetween 字段不在 start 和 end 之间
+func (b *Builder) WhereNotBetween(column string, start, end interface{}) *Builder {
+	return b.whereBetween("AND", column, start, end, true)
+}
+
+// OrWhereNotBetween 或者字段不在 start 和 end 之间
+func (b *Builder) OrWhereNotBetween(column string, start, end interface{}) *Builder {
+	return b.whereBetween("OR", column, start, end, true)
+}
+
+// WhereColumn 比较两个字段，例如：WhereColumn("updated_at", ">", "created_at")
+func (b *Builder) WhereColumn(first, operator, second string) *Builder {
+	return b.whereColumn("AND", first, operator, second)
+}
+
+// OrWhereColumn 或者比较两个字段
+func (b *Builder) OrWhereColumn(first, operator, second string) *Builder {
+	return b.whereColumn("OR", first, operator, second)
+}
+
+// WhereExists 子查询存在数据
+func (b *Builder) WhereExists(query *Builder) *Builder {
+	return b.whereExists("AND", query, false)
+}
+
+// OrWhereExists 或者子查询存在数据
+func (b *Builder) OrWhereExists(query *Builder) *Builder {
+	return b.whereExists("OR", query, false)
+}
+
+// WhereNotExists 子查询不存在数据
+func (b *Builder) WhereNotExists(query *Builder) *Builder {
+	return b.whereExists("AND", query, true)
+}
+
+// OrWhereNotExists 或者子查询不存在数据
+func (b *Builder) OrWhereNotExists(query *Builder) *Builder {
+	return b.whereExists("OR", query, true)
+}
+
+func (b *Builder) whereNull(boolean, column string, not bool) *Builder {
+	if !b.identifier(column) {
+		return b
+	}
+
+	operator := "IS NULL"
+	if not {
+		operator = "IS NOT NULL"
+	}
+
+	b.wheres = append(b.wheres, fmt.Sprintf("%s %s %s", boolean, b.warp(column), operator))
+	return b
+}
+
+func (b *Builder) whereLike(boolean, column, value string, not bool) *Builder {
+	if !b.identifier(column) {
+		return b
+	}
+
+	operator := "LIKE"
+	if not {
+		operator = "NOT LIKE"
+	}
+
+	b.wheres = append(b.wheres, fmt.Sprintf("%s %s %s ?", boolean, b.warp(column), operator))
+	b.bindings = append(b.bindings, "%"+EscapeLike(value)+"%")
+	return b
+}
+
+func (b *Builder) whereIn(boolean, column string, values interface{}, not bool) *Builder {
+	if !b.identifier(column) {
+		return b
+	}
+
+	value := reflect.ValueOf(values)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		values = []interface{}{values}
+	} else if value.Len() == 0 {
+		// 空的 IN 查询条件不成立，空的 NOT IN 查询条件恒成立
+		if not {
+			b.wheres = append(b.wheres, fmt.Sprintf("%s 1 = 1", boolean))
+		} else {
+			b.wheres = append(b.wheres, fmt.Sprintf("%s 0 = 1", boolean))
+		}
+
+		return b
+	}
+
+	operator := "IN"
+	if not {
+		operator = "NOT IN"
+	}
+
+	b.wheres = append(b.wheres, fmt.Sprintf("%s %s %s (?)", boolean, b.warp(column), operator))
+	b.bindings = append(b.bindings, values)
+	return b
+}
+
+func (b *Builder) whereBetween(boolean, column string, start, end interface{}, not bool) *Builder {
+	if !b.identifier(column) {
+		return b
+	}
+
+	operator := "BETWEEN"
+	if not {
+		operator = "NOT BETWEEN"
+	}
+
+	b.wheres = append(b.wheres, fmt.Sprintf("%s %s %s ? AND ?", boolean, b.warp(column), operator))
+	b.bindings = append(b.bindings, start, end)
+	return b
+}
+
+func (b *Builder) whereColumn(boolean, first, operator, second string) *Builder {
+	if !columnOperators[operator] {
+		return b.addError(fmt.Errorf("where column %s has unsupported operator %q", first, operator))
+	}
+
+	if !b.identifier(first) || !b.identifier(second) {
+		return b
+	}
+
+	b.wheres = append(b.wheres, fmt.Sprintf("%s %s %s %s", boolean, b.warp(first), operator, b.warp(second)))
+	return b
+}
+
+func (b *Builder) whereExists(boolean string, query *Builder, not bool) *Builder {
+	operator := "EXISTS"
+	if not {
+		operator = "NOT EXISTS"
+	}
+
+	b.wheres = append(b.wheres, fmt.Sprintf("%s %s (%s)", boolean, operator, query.String()))
+	b.bindings = append(b.bindings, query.getBindings()...)
+	b.errs = append(b.errs, query.errs...)
+	return b
+}
@@ -0,0 +1,88 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapeLike(t *testing.T) {
+	assert.Equal(t, `100\% \_ok\\`, EscapeLike(`100% _ok\`))
+}
+
+func TestBuilder_WhereNull(t *testing.T) {
+	b := NewBuilder(&MySQl{}, &User{}).
+		WhereNull("deleted_at").
+		OrWhereNull("user.created_at").
+		WhereNotNull("updated_at").
+		OrWhereNotNull("status")
+	assert.Equal(t, "SELECT * FROM `user` WHERE `deleted_at` IS NULL OR `user`.`created_at` IS NULL AND `updated_at` IS NOT NULL OR `status` IS NOT NULL", b.String())
+	assert.Equal(t, 0, len(b.bindings))
+}
+
+func TestBuilder_WhereLike(t *testing.T) {
+	b := NewBuilder(&MySQl{}, &User{}).
+		WhereLike("username", "50%_off").
+		OrWhereLike("password", "test").
+		WhereNotLike("username", "admin").
+		OrWhereNotLike("password", "root")
+	assert.Equal(t, "SELECT * FROM `user` WHERE `username` LIKE ? OR `password` LIKE ? AND `username` NOT LIKE ? OR `password` NOT LIKE ?", b.String())
+	assert.Equal(t, []interface{}{`%50\%\_off%`, "%test%", "%admin%", "%root%"}, b.bindings)
+}
+
+func TestBuilder_WhereIn(t *testing.T) {
+	b := NewBuilder(&MySQl{}, &User{}).
+		WhereIn("status", []int{1, 2}).
+		OrWhereIn("user_id", 1).
+		WhereNotIn("username", []string{"test1"}).
+		OrWhereNotIn("password", []string{"123"})
+	assert.Equal(t, "SELECT * FROM `user` WHERE `status` IN (?) OR `user_id` IN (?) AND `username` NOT IN (?) OR `password` NOT IN (?)", b.String())
+	assert.Equal(t, []interface{}{[]int{1, 2}, []interface{}{1}, []string{"test1"}, []string{"123"}}, b.bindings)
+
+	// 空的查询条件
+	b = NewBuilder(&MySQl{}, &User{}).WhereIn("status", []int{}).WhereNotIn("user_id", []int{})
+	assert.Equal(t, "SELECT * FROM `user` WHERE 0 = 1 AND 1 = 1", b.String())
+	assert.Equal(t, 0, len(b.bindings))
+}
+
+func TestBuilder_WhereBetween(t *testing.T) {
+	b := NewBuilder(&MySQl{}, &User{}).
+		WhereBetween("status", 1, 2).
+		OrWhereBetween("user_id", 1, 10).
+		WhereNotBetween("created_at", "2020-11-01", "2020-11-30").
+		OrWhereNotBetween("updated_at", "2020-11-01", "2020-11-30")
+	assert.Equal(t, "SELECT * FROM `user` WHERE `status` BETWEEN ? AND ? OR `user_id` BETWEEN ? AND ? AND `created_at` NOT BETWEEN ? AND ? OR `updated_at` NOT BETWEEN ? AND ?", b.String())
+	assert.Equal(t, []interface{}{1, 2, 1, 10, "2020-11-01", "2020-11-30", "2020-11-01", "2020-11-30"}, b.bindings)
+}
+
+func TestBuilder_WhereColumn(t *testing.T) {
+	b := NewBuilder(&MySQl{}, &User{}).
+		WhereColumn("updated_at", ">", "created_at").
+		OrWhereColumn("user.user_id", "=", "status")
+	assert.Equal(t, "SELECT * FROM `user` WHERE `updated_at` > `created_at` OR `user`.`user_id` = `status`", b.String())
+}
+
+func TestBuilder_WhereExists(t *testing.T) {
+	sub := NewBuilder(&MySQl{}, &User{}).Table("user as u").WhereColumn("u.user_id", "=", "user.user_id").Where("u.status", 1)
+	b := NewBuilder(&MySQl{}, &User{}).
+		Where("status", 2).
+		WhereExists(sub).
+		OrWhereNotExists(sub)
+	assert.Equal(t, "SELECT * FROM `user` WHERE `status` = ? AND EXISTS (SELECT * FROM `user` AS `u` WHERE `u`.`user_id` = `user`.`user_id` AND `u`.`status` = ?) OR NOT EXISTS (SELECT * FROM `user` AS `u` WHERE `u`.`user_id` = `user`.`user_id` AND `u`.`status` = ?)", b.String())
+	assert.Equal(t, []interface{}{2, 1, 1}, b.bindings)
+}
+
+func TestBuilder_WhereColumnErr(t *testing.T) {
+	b := NewBuilder(&MySQl{}, &User{}).WhereColumn("updated_at", "; DROP", "created_at")
+	assert.EqualError(t, b.Err(), `where column updated_at has unsupported operator "; DROP"`)
+
+	// 子查询的错误
+	b = NewBuilder(&MySQl{}, &User{}).WhereExists(NewBuilder(&MySQl{}, &User{}).Where(1))
+	assert.Error(t, b.Err())
+}
+
+func TestBuilder_WhereNotExistsFirst(t *testing.T) {
+	sub := NewBuilder(&MySQl{}, &User{}).Where("status", 1)
+	s := NewBuilder(&MySQl{}, &User{}).WhereNotExists(sub).String()
+	assert.Equal(t, "SELECT * FROM `user` WHERE NOT EXISTS (SELECT * FROM `user` WHERE `status` = ?)", s)
+}
@@ -0,0 +1,139 @@
+package mysql
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// windowFuncRegexp 窗口函数，例如：ROW_NUMBER()、RANK()、SUM(amount)、LAG(amount, 1)
+var windowFuncRegexp = regexp.MustCompile(`^([A-Za-z_]+)\s*\((.*)\)$`)
+
+// windowArgRegexp 窗口函数的数字参数
+var windowArgRegexp = regexp.MustCompile(`^\d+$`)
+
+// SelectWindow 查询窗口函数，例如：SelectWindow("ROW_NUMBER()", []string{"status"}, []string{"created_at desc"}, "row_num")
+// orderBy 的格式和 OrderBySafe 一致：created_at、-created_at、created_at:desc、created_at desc
+func (b *Builder) SelectWindow(fn string, partitionBy, orderBy []string, alias string) *Builder {
+	function, ok := b.windowFunc(fn)
+	if !ok || !b.identifier(alias) {
+		return b
+	}
+
+	spec, ok := b.windowSpec(partitionBy, orderBy)
+	if !ok {
+		return b
+	}
+
+	b.selects = append(b.selects, fmt.Sprintf("%s OVER (%s) AS %s", function, spec, b.warp(alias)))
+	return b
+}
+
+// SelectWindowNamed 使用命名窗口查询窗口函数，窗口通过 Window 定义
+func (b *Builder) SelectWindowNamed(fn, window, alias string) *Builder {
+	function, ok := b.windowFunc(fn)
+	if !ok || !b.identifier(window) || !b.identifier(alias) {
+		return b
+	}
+
+	b.selects = append(b.selects, fmt.Sprintf("%s OVER %s AS %s", function, b.warp(window), b.warp(alias)))
+	return b
+}
+
+// Window 定义命名窗口，WINDOW `name` AS (PARTITION BY ... ORDER BY ...)
+func (b *Builder) Window(name string, partitionBy, orderBy []string) *Builder {
+	if !b.identifier(name) {
+		return b
+	}
+
+	spec, ok := b.windowSpec(partitionBy, orderBy)
+	if !ok {
+		return b
+	}
+
+	b.windows = append(b.windows, fmt.Sprintf("%s AS (%s)", b.warp(name), spec))
+	return b
+}
+
+// WithRollup 分组统计增加汇总行，需要先调用 GroupBy
+func (b *Builder) WithRollup() *Builder {
+	if len(b.groups) == 0 {
+		return b.addError(errors.New("with rollup needs GroupBy"))
+	}
+
+	b.rollup = true
+	return b
+}
+
+// windowFunc 验证并格式化窗口函数
+func (b *Builder) windowFunc(fn string) (string, bool) {
+	matches := windowFuncRegexp.FindStringSubmatch(strings.TrimSpace(fn))
+	if matches == nil {
+		b.addError(fmt.Errorf("invalid window function %q", fn))
+		return "", false
+	}
+
+	args := make([]string, 0)
+	if arg := strings.TrimSpace(matches[2]); arg != "" {
+		for _, v := range strings.Split(arg, ",") {
+			v = strings.TrimSpace(v)
+			switch {
+			case windowArgRegexp.MatchString(v):
+				args = append(args, v)
+			case b.identifier(v):
+				args = append(args, b.warp(v))
+			default:
+				return "", false
+			}
+		}
+	}
+
+	return fmt.Sprintf("%s(%s)", strings.ToUpper(matches[1]), strings.Join(args, ", ")), true
+}
+
+// windowSpec 窗口的 PARTITION BY 和 ORDER BY
+func (b *Builder) windowSpec(partitionBy, orderBy []string) (string, bool) {
+	spec := make([]string, 0, 2)
+	if len(partitionBy) > 0 {
+		columns := make([]string, len(partitionBy))
+		for k, v := range partitionBy {
+			if !b.identifier(v) {
+				return "", false
+			}
+
+			columns[k] = b.warp(v)
+		}
+
+		spec = append(spec, "PARTITION BY "+strings.Join(columns, ", "))
+	}
+
+	if len(orderBy) > 0 {
+		orders := make([]string, len(orderBy))
+		for k, v := range orderBy {
+			column, direction := parseOrder(v)
+			if column == "" {
+				b.addError(fmt.Errorf("invalid window order %q", v))
+				return "", false
+			}
+
+			if !b.identifier(column) {
+				return "", false
+			}
+
+			orders[k] = fmt.Sprintf("%s %s", b.warp(column), strings.ToUpper(direction))
+		}
+
+		spec = append(spec, "ORDER BY "+strings.Join(orders, ", "))
+	}
+
+	return strings.Join(spec, " "), true
+}
+
+func (b *Builder) windowFormat() string {
+	if len(b.windows) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(" WINDOW %s", strings.Join(b.windows, ", "))
+}
@@ -0,0 +1,60 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder_SelectWindow(t *testing.T) {
+	b := NewBuilder(&MySQl{}, &User{}).
+		Select("user_id", "status").
+		SelectWindow("ROW_NUMBER()", []string{"status"}, []string{"created_at desc", "user_id"}, "row_num").
+		SelectWindow("sum(user_id)", nil, []string{"-user_id"}, "running_total").
+		SelectWindow("LAG(username, 1)", []string{"status"}, nil, "prev_name")
+	assert.NoError(t, b.Err())
+	assert.Equal(t, "SELECT `user_id`, `status`, ROW_NUMBER() OVER (PARTITION BY `status` ORDER BY `created_at` DESC, `user_id` ASC) AS `row_num`, SUM(`user_id`) OVER (ORDER BY `user_id` DESC) AS `running_total`, LAG(`username`, 1) OVER (PARTITION BY `status`) AS `prev_name` FROM `user`", b.String())
+}
+
+func TestBuilder_Window(t *testing.T) {
+	b := NewBuilder(&MySQl{}, &User{}).
+		SelectWindowNamed("RANK()", "w", "rank_num").
+		SelectWindowNamed("DENSE_RANK()", "w", "dense_rank_num").
+		Window("w", []string{"status"}, []string{"created_at:desc"}).
+		OrderBy("user_id", "asc")
+	assert.NoError(t, b.Err())
+	assert.Equal(t, "SELECT *, RANK() OVER `w` AS `rank_num`, DENSE_RANK() OVER `w` AS `dense_rank_num` FROM `user` WINDOW `w` AS (PARTITION BY `status` ORDER BY `created_at` DESC) ORDER BY `user_id` ASC", b.String())
+}
+
+func TestBuilder_SelectWindowErr(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *Builder
+	}{
+		{name: "函数错误", builder: NewBuilder(&MySQl{}, &User{}).SelectWindow("ROW_NUMBER() OVER () AS x, (SELECT 1)", nil, nil, "r")},
+		{name: "函数参数错误", builder: NewBuilder(&MySQl{}, &User{}).SelectWindow("SUM(1 + 1)", nil, nil, "r")},
+		{name: "分区字段错误", builder: NewBuilder(&MySQl{}, &User{}).SelectWindow("RANK()", []string{"status;"}, nil, "r")},
+		{name: "排序错误", builder: NewBuilder(&MySQl{}, &User{}).SelectWindow("RANK()", nil, []string{"status up"}, "r")},
+		{name: "别名错误", builder: NewBuilder(&MySQl{}, &User{}).SelectWindowNamed("RANK()", "w", "r)")},
+		{name: "窗口名称错误", builder: NewBuilder(&MySQl{}, &User{}).Window("w)", nil, nil)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, tt.builder.Err())
+			assert.Equal(t, "SELECT * FROM `user`", tt.builder.String())
+		})
+	}
+}
+
+func TestBuilder_WithRollup(t *testing.T) {
+	b := NewBuilder(&MySQl{}, &User{}).Select("status").GroupBy("status").WithRollup()
+	assert.NoError(t, b.Err())
+	assert.Equal(t, "SELECT `status` FROM `user` GROUP BY `status` WITH ROLLUP", b.String())
+
+	query, _ := b.countSQL()
+	assert.Equal(t, "SELECT COUNT(*) AS `total` FROM (SELECT `status` FROM `user` GROUP BY `status` WITH ROLLUP) AS `aggregate`", query)
+
+	b = NewBuilder(&MySQl{}, &User{}).WithRollup()
+	assert.EqualError(t, b.Err(), "with rollup needs GroupBy")
+}